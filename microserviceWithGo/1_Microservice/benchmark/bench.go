@@ -0,0 +1,229 @@
+// Package benchmark compares the transports of the HelloWorld contract: gob
+// and JSON-RPC over TCP, JSON-RPC over HTTP and gRPC. Every server runs on
+// loopback in the same process and is driven by a number of concurrent
+// workers with names of a given size, so the numbers show the cost of the
+// protocols rather than of the network.
+package benchmark
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// maxConsecutiveErrors stops a worker whose calls keep failing, e.g. because
+// the server went away, instead of spinning until the end of the run
+const maxConsecutiveErrors = 10
+
+// Config is the load applied to every transport, each combination of
+// Concurrency and PayloadSizes is one run
+type Config struct {
+	// Concurrency is the number of workers calling at once, each with its
+	// own connection. Defaults to 1.
+	Concurrency []int
+	// PayloadSizes are the lengths of the name sent, the reply is a few
+	// bytes longer. Defaults to 16.
+	PayloadSizes []int
+	// Duration of every run, defaults to one second.
+	Duration time.Duration
+	// Warmup calls made by every worker before measuring, defaults to 10.
+	Warmup int
+}
+
+// Result is the outcome of one run
+type Result struct {
+	Transport   string
+	Concurrency int
+	PayloadSize int
+	Calls       int
+	Errors      int
+	Elapsed     time.Duration
+	// Throughput is the number of successful calls per second.
+	Throughput float64
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+	Max        time.Duration
+	// AllocsPerCall and BytesPerCall are taken from the heap statistics of
+	// the process, so they include the server side of the call.
+	AllocsPerCall float64
+	BytesPerCall  float64
+}
+
+// Run starts each transport in turn and drives it with every combination of
+// the config
+func Run(ctx context.Context, transports []Transport, config Config) ([]Result, error) {
+	config = config.withDefaults()
+
+	var results []Result
+	for _, t := range transports {
+		dial, stop, err := t.Start()
+		if err != nil {
+			return results, fmt.Errorf("benchmark: starting %v: %w", t.Name, err)
+		}
+
+		for _, concurrency := range config.Concurrency {
+			for _, size := range config.PayloadSizes {
+				r, err := run(ctx, dial, concurrency, size, config)
+				if err != nil {
+					stop()
+					return results, fmt.Errorf("benchmark: %v: %w", t.Name, err)
+				}
+				r.Transport = t.Name
+				results = append(results, r)
+			}
+		}
+
+		stop()
+	}
+
+	return results, nil
+}
+
+func (c Config) withDefaults() Config {
+	if len(c.Concurrency) == 0 {
+		c.Concurrency = []int{1}
+	}
+	if len(c.PayloadSizes) == 0 {
+		c.PayloadSizes = []int{16}
+	}
+	if c.Duration <= 0 {
+		c.Duration = time.Second
+	}
+	if c.Warmup <= 0 {
+		c.Warmup = 10
+	}
+
+	return c
+}
+
+// run drives one transport with the given number of workers
+func run(ctx context.Context, dial func() (Client, error), concurrency, size int, config Config) (Result, error) {
+	clients := make([]Client, concurrency)
+	defer func() {
+		for _, c := range clients {
+			if c != nil {
+				c.Close()
+			}
+		}
+	}()
+
+	name := strings.Repeat("x", size)
+	for i := range clients {
+		c, err := dial()
+		if err != nil {
+			return Result{}, err
+		}
+		clients[i] = c
+
+		for j := 0; j < config.Warmup; j++ {
+			if _, err := c.HelloWorld(ctx, name); err != nil {
+				return Result{}, fmt.Errorf("warmup: %w", err)
+			}
+		}
+	}
+
+	latencies := make([][]time.Duration, concurrency)
+	failures := make([]int, concurrency)
+	errs := make([]error, concurrency)
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	deadline := start.Add(config.Duration)
+
+	var wg sync.WaitGroup
+	for i, c := range clients {
+		wg.Add(1)
+		go func(i int, c Client) {
+			defer wg.Done()
+
+			consecutive := 0
+			for time.Now().Before(deadline) && ctx.Err() == nil {
+				callStart := time.Now()
+				if _, err := c.HelloWorld(ctx, name); err != nil {
+					failures[i]++
+					if consecutive++; consecutive >= maxConsecutiveErrors {
+						errs[i] = fmt.Errorf("worker %v gave up after %v errors in a row: %w", i, consecutive, err)
+						return
+					}
+					continue
+				}
+				consecutive = 0
+				latencies[i] = append(latencies[i], time.Since(callStart))
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	for _, err := range errs {
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	r := Result{Concurrency: concurrency, PayloadSize: size, Elapsed: elapsed}
+	var all []time.Duration
+	for i := range latencies {
+		all = append(all, latencies[i]...)
+		r.Errors += failures[i]
+	}
+	r.Calls = len(all) + r.Errors
+	if r.Calls == 0 {
+		return r, nil
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	r.Throughput = float64(len(all)) / elapsed.Seconds()
+	r.P50 = percentile(all, 0.50)
+	r.P90 = percentile(all, 0.90)
+	r.P99 = percentile(all, 0.99)
+	if len(all) > 0 {
+		r.Max = all[len(all)-1]
+	}
+	// growing the latency slices allocates too, amortized that is far below
+	// one allocation per call
+	r.AllocsPerCall = float64(after.Mallocs-before.Mallocs) / float64(r.Calls)
+	r.BytesPerCall = float64(after.TotalAlloc-before.TotalAlloc) / float64(r.Calls)
+
+	return r, nil
+}
+
+// percentile returns the latency below which the fraction p of the sorted
+// latencies lies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[int(p*float64(len(sorted)-1))]
+}
+
+// WriteTable writes the results as an aligned table
+func WriteTable(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "transport\tconc\tpayload\tcalls\terrors\tcalls/s\tp50\tp90\tp99\tmax\tallocs/call\tB/call\t")
+
+	for _, r := range results {
+		fmt.Fprintf(tw, "%v\t%v\t%v\t%v\t%v\t%.0f\t%v\t%v\t%v\t%v\t%.1f\t%.0f\t\n",
+			r.Transport, r.Concurrency, r.PayloadSize, r.Calls, r.Errors, r.Throughput,
+			round(r.P50), round(r.P90), round(r.P99), round(r.Max), r.AllocsPerCall, r.BytesPerCall)
+	}
+
+	return tw.Flush()
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,138 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunsEveryTransport(t *testing.T) {
+	results, err := Run(context.Background(), Transports, Config{
+		Concurrency:  []int{1, 4},
+		PayloadSizes: []int{16, 4096},
+		Duration:     20 * time.Millisecond,
+		Warmup:       1,
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, len(Transports)*4, len(results))
+	for _, r := range results {
+		assert.True(t, r.Calls > 0, "%v made no calls", r.Transport)
+		assert.Equal(t, 0, r.Errors, r.Transport)
+		assert.True(t, r.P50 <= r.P90 && r.P90 <= r.P99 && r.P99 <= r.Max, r.Transport)
+		assert.True(t, r.AllocsPerCall > 0, r.Transport)
+	}
+}
+
+func TestTransportsEchoName(t *testing.T) {
+	name := strings.Repeat("x", 70000)
+
+	for _, tr := range Transports {
+		dial, stop, err := tr.Start()
+		assert.Nil(t, err)
+
+		c, err := dial()
+		assert.Nil(t, err)
+
+		reply, err := c.HelloWorld(context.Background(), name)
+		assert.Nil(t, err, tr.Name)
+		assert.Equal(t, "Hello "+name, reply, tr.Name)
+
+		c.Close()
+		stop()
+	}
+}
+
+// failingClient succeeds for the first calls and fails afterwards
+type failingClient struct {
+	succeed int
+	calls   int
+}
+
+func (c *failingClient) HelloWorld(ctx context.Context, name string) (string, error) {
+	c.calls++
+	if c.calls > c.succeed {
+		return "", errors.New("connection refused")
+	}
+
+	return "Hello " + name, nil
+}
+
+func (c *failingClient) Close() error {
+	return nil
+}
+
+func TestStopsWorkerAfterConsecutiveErrors(t *testing.T) {
+	c := &failingClient{succeed: 1}
+	failing := Transport{Name: "failing", Start: func() (func() (Client, error), func(), error) {
+		return func() (Client, error) { return c, nil }, func() {}, nil
+	}}
+
+	start := time.Now()
+	_, err := Run(context.Background(), []Transport{failing}, Config{Duration: time.Minute, Warmup: 1})
+
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) < time.Second)
+	assert.Equal(t, 1+maxConsecutiveErrors, c.calls)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 0.5))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 0.99))
+	assert.Equal(t, time.Duration(0), percentile(nil, 0.5))
+}
+
+func TestWritesTable(t *testing.T) {
+	var buf bytes.Buffer
+	WriteTable(&buf, []Result{{Transport: "gob", Concurrency: 8, PayloadSize: 16, Calls: 1000, Throughput: 5000, P50: 1500 * time.Nanosecond}})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], "calls/s")
+	assert.Contains(t, lines[1], "gob")
+	assert.Contains(t, lines[1], "2µs")
+}
+
+// go test -run none -bench . -benchmem ./1_Microservice/benchmark
+func BenchmarkTransports(b *testing.B) {
+	for _, tr := range Transports {
+		for _, size := range []int{16, 4096} {
+			b.Run(fmt.Sprintf("%v/%v", tr.Name, size), func(b *testing.B) {
+				dial, stop, err := tr.Start()
+				if err != nil {
+					b.Fatal(err)
+				}
+				defer stop()
+
+				b.ReportAllocs()
+				b.RunParallel(func(pb *testing.PB) {
+					c, err := dial()
+					if err != nil {
+						b.Error(err)
+						return
+					}
+					defer c.Close()
+
+					name := strings.Repeat("x", size)
+					for pb.Next() {
+						if _, err := c.HelloWorld(context.Background(), name); err != nil {
+							b.Error(err)
+							return
+						}
+					}
+				})
+			})
+		}
+	}
+}
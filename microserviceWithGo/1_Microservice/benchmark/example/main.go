@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/benchmark"
+)
+
+// go run ./1_Microservice/benchmark/example -concurrency 1,16 -payload 16,4096 -duration 2s
+func main() {
+	concurrency := flag.String("concurrency", "1,8,64", "comma separated numbers of concurrent workers")
+	payload := flag.String("payload", "16,1024,65536", "comma separated sizes of the name in bytes")
+	transports := flag.String("transports", "gob,jsonrpc,http-json,grpc", "comma separated transports to compare")
+	duration := flag.Duration("duration", time.Second, "duration of every run")
+	flag.Parse()
+
+	config := benchmark.Config{
+		Concurrency:  ints(*concurrency),
+		PayloadSizes: ints(*payload),
+		Duration:     *duration,
+	}
+
+	// 선택한 전송 방식만 실행
+	var selected []benchmark.Transport
+	for _, name := range strings.Split(*transports, ",") {
+		found := false
+		for _, t := range benchmark.Transports {
+			if t.Name == strings.TrimSpace(name) {
+				selected = append(selected, t)
+				found = true
+			}
+		}
+		if !found {
+			log.Fatalf("unknown transport %q", name)
+		}
+	}
+
+	results, err := benchmark.Run(context.Background(), selected, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	benchmark.WriteTable(os.Stdout, results)
+}
+
+func ints(s string) []int {
+	var values []int
+	for _, f := range strings.Split(s, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil || n <= 0 {
+			log.Fatalf("invalid number %q", f)
+		}
+		values = append(values, n)
+	}
+
+	return values
+}
@@ -0,0 +1,221 @@
+package benchmark
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	rpcserver "github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/server"
+	jsonserver "github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_http_json/server"
+	multiserver "github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_multi/server"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittens"
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// dialTimeout bounds how long connecting a gRPC client may take
+const dialTimeout = 5 * time.Second
+
+// Client calls HelloWorld on a server, every worker has its own Client
+type Client interface {
+	HelloWorld(ctx context.Context, name string) (string, error)
+	Close() error
+}
+
+// Transport starts a HelloWorld server on loopback, Start returns a function
+// which connects a new Client and one which stops the server
+type Transport struct {
+	Name  string
+	Start func() (dial func() (Client, error), stop func(), err error)
+}
+
+// Transports are the four implementations of the HelloWorld contract
+var Transports = []Transport{
+	{Name: "gob", Start: startGob},
+	{Name: "jsonrpc", Start: startJSONRPC},
+	{Name: "http-json", Start: startHTTPJSON},
+	{Name: "grpc", Start: startGRPC},
+}
+
+func listen() (net.Listener, error) {
+	return net.Listen("tcp", "127.0.0.1:0")
+}
+
+// rpcClient is a net/rpc client with either codec
+type rpcClient struct {
+	*rpc.Client
+}
+
+func (c rpcClient) HelloWorld(ctx context.Context, name string) (string, error) {
+	var reply contract.HelloWorldResponse
+	err := c.Call(contract.HelloWorldMethod, &contract.HelloWorldRequest{Name: name}, &reply)
+
+	return reply.Message, err
+}
+
+// startGob serves net/rpc with gob over TCP using the rpc server
+func startGob() (func() (Client, error), func(), error) {
+	r := rpc.NewServer()
+	r.RegisterName(contract.HelloWorldService, &rpcserver.HelloWorldHandler{})
+
+	l, err := listen()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &rpcserver.Server{RPC: r}
+	go s.Serve(l)
+
+	dial := func() (Client, error) {
+		c, err := rpc.Dial("tcp", l.Addr().String())
+		return rpcClient{c}, err
+	}
+
+	return dial, func() { s.Stop() }, nil
+}
+
+// startJSONRPC serves JSON-RPC 1.0 over TCP using the multi codec server
+func startJSONRPC() (func() (Client, error), func(), error) {
+	s := multiserver.New()
+	s.RegisterName(contract.HelloWorldService, &multiserver.HelloWorldHandler{})
+
+	l, err := listen()
+	if err != nil {
+		return nil, nil, err
+	}
+	go s.Serve(l)
+
+	dial := func() (Client, error) {
+		c, err := jsonrpc.Dial("tcp", l.Addr().String())
+		return rpcClient{c}, err
+	}
+
+	return dial, func() { s.Close() }, nil
+}
+
+// httpClient sends JSON-RPC 2.0 requests as HTTP POSTs
+type httpClient struct {
+	url    string
+	client *http.Client
+}
+
+func (c *httpClient) HelloWorld(ctx context.Context, name string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  contract.HelloWorldMethod,
+		"params":  contract.HelloWorldRequest{Name: name},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var reply struct {
+		Result struct {
+			Message string `json:"message"`
+		} `json:"result"`
+		Error *jsonserver.Error `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		return "", err
+	}
+	if reply.Error != nil {
+		return "", reply.Error
+	}
+
+	return reply.Result.Message, nil
+}
+
+func (c *httpClient) Close() error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+// startHTTPJSON serves JSON-RPC 2.0 over HTTP using the rpc_http_json handler
+func startHTTPJSON() (func() (Client, error), func(), error) {
+	r := jsonserver.NewServer()
+	r.Register(&jsonserver.HelloWorldHandler{})
+
+	l, err := listen()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := &http.Server{Handler: jsonserver.NewHandler(r)}
+	go s.Serve(l)
+
+	dial := func() (Client, error) {
+		return &httpClient{
+			url:    fmt.Sprintf("http://%v/", l.Addr()),
+			client: &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: 1}},
+		}, nil
+	}
+
+	return dial, func() { s.Close() }, nil
+}
+
+type grpcClient struct {
+	conn   *grpc.ClientConn
+	client proto.KittensClient
+}
+
+func (c *grpcClient) HelloWorld(ctx context.Context, name string) (string, error) {
+	response, err := c.client.Hello(ctx, &proto.Request{Name: name})
+	if err != nil {
+		return "", err
+	}
+
+	return response.Msg, nil
+}
+
+func (c *grpcClient) Close() error {
+	return c.conn.Close()
+}
+
+// startGRPC serves the Hello RPC of the Kittens service using the grpc
+// server implementation, names must not be empty
+func startGRPC() (func() (Client, error), func(), error) {
+	l, err := listen()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s := grpc.NewServer()
+	proto.RegisterKittensServer(s, kittens.New(&data.MemoryStore{}))
+	go s.Serve(l)
+
+	dial := func() (Client, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		defer cancel()
+
+		conn, err := grpc.DialContext(ctx, l.Addr().String(), grpc.WithInsecure(), grpc.WithBlock())
+		if err != nil {
+			return nil, err
+		}
+
+		return &grpcClient{conn: conn, client: proto.NewKittensClient(conn)}, nil
+	}
+
+	return dial, s.Stop, nil
+}
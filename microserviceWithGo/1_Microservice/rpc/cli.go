@@ -2,17 +2,30 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/client"
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/server"
 )
 
 func main() {
-	go server.StartServer()
+	go func() {
+		if err := server.StartServer(); err != nil {
+			log.Fatal(err)
+		}
+	}()
+	time.Sleep(100 * time.Millisecond) // 서버가 포트를 열 때까지 대기
 
-	c := client.CreateClient()
+	c, err := client.CreateClient()
+	if err != nil {
+		log.Fatal("dialing:", err)
+	}
 	defer c.Close()
 
-	reply := client.PerformRequest(c)
+	reply, err := client.PerformRequest(c)
+	if err != nil {
+		log.Fatal("error:", err)
+	}
 	fmt.Println(reply.Message)
 }
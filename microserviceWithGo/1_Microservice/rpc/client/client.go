@@ -1,34 +1,224 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"net"
 	"net/rpc"
+	"reflect"
+	"sync"
+	"time"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/registry"
 )
 
+//go:generate go run ./gen
+
 const port = 1234
 
-func CreateClient() *rpc.Client {
+// ErrClosed is returned by calls on a closed Client
+var ErrClosed = errors.New("client: closed")
+
+// errNotSent is returned by call when the connection was shut down before
+// the call could be sent, only these calls are safe to retry
+var errNotSent = errors.New("client: call not sent")
+
+// Client calls the contract services over net/rpc. The connection is opened
+// on the first call and opened again after it drops, so a Client can be kept
+// while the server restarts.
+type Client struct {
+	// Addr is the address of the server.
+	Addr string
+	// Timeout is used for calls whose context has no deadline, 0 waits for
+	// the reply forever.
+	Timeout time.Duration
+
+	mutex  sync.Mutex
+	conn   *conn
+	closed bool
+}
+
+// conn is a connection to the server, it is only closed with the write lock
+// held so that send can tell calls which were never sent apart from calls
+// which were cut off by Close
+type conn struct {
+	*rpc.Client
+	mutex sync.RWMutex
+}
+
+// send sends the call, errNotSent is returned when the connection had
+// already been shut down
+func (c *conn) send(serviceMethod string, args interface{}, reply interface{}) (*rpc.Call, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	call := c.Go(serviceMethod, args, reply, make(chan *rpc.Call, 1))
+	select {
+	case <-call.Done:
+		// Close can not run while the lock is held, so net/rpc only returns
+		// ErrShutdown this early for calls which it refused to send
+		if call.Error == rpc.ErrShutdown {
+			return nil, errNotSent
+		}
+		call.Done <- call
+	default:
+	}
+
+	return call, nil
+}
+
+func (c *conn) close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.Client.Close()
+}
+
+// Dial connects to the server at addr, an error is returned when it can not
+// be reached
+func Dial(addr string) (*Client, error) {
+	c := &Client{Addr: addr}
+	if _, err := c.connect(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func CreateClient() (*Client, error) {
 	// Dial() 을 사용해 클라이언트 자체를 생성
-	client, err := rpc.Dial("tcp", fmt.Sprintf("localhost:%v", port))
+	return Dial(fmt.Sprintf("localhost:%v", port))
+}
+
+func PerformRequest(client *Client) (contract.HelloWorldResponse, error) {
+	reply, err := client.HelloWorld(context.Background(), &contract.HelloWorldRequest{Name: "World"})
 	if err != nil {
-		log.Fatal("dialing:", err)
+		return contract.HelloWorldResponse{}, err
 	}
 
-	return client
+	return *reply, nil
 }
 
-func PerformRequest(client *rpc.Client) contract.HelloWorldResponse {
-	args := &contract.HelloWorldRequest{Name: "World"}
-	var reply contract.HelloWorldResponse
+// Services lists the services offered by the server
+func (c *Client) Services(ctx context.Context) ([]registry.Service, error) {
+	reply := &registry.ListResponse{}
+	if err := c.Call(ctx, registry.ListMethod, &registry.ListRequest{}, reply); err != nil {
+		return nil, err
+	}
 
-	// 클라이언트의 Call() 메서드를 사용해 서버의 이름 붙여진 함수를 호출한다.
-	err := client.Call("HelloWorldHandler.HelloWorld", args, &reply)
+	return reply.Services, nil
+}
+
+// Call calls serviceMethod and waits for the reply until ctx is done. net/rpc
+// can not cancel a call which has been sent, its reply is discarded when it
+// arrives after Call returned.
+//
+// Errors returned by the service are rpc.ServerError, any other error means
+// the connection failed and the next call opens a new one. Calls which were
+// never sent because the connection was already shut down are retried once
+// on a new connection, calls which may have reached the server are not.
+func (c *Client) Call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	if _, ok := ctx.Deadline(); !ok && c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	err := c.call(ctx, serviceMethod, args, reply)
+	if err == errNotSent && ctx.Err() == nil {
+		err = c.call(ctx, serviceMethod, args, reply)
+	}
+	if err == errNotSent {
+		return rpc.ErrShutdown
+	}
+
+	return err
+}
+
+func (c *Client) call(ctx context.Context, serviceMethod string, args interface{}, reply interface{}) error {
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	// 늦게 도착한 응답이 reply 를 덮어쓰지 않도록 새 값에 받은 다음 복사
+	v := reflect.ValueOf(reply)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("client: reply must be a non-nil pointer, got %T", reply)
+	}
+	fresh := reflect.New(v.Elem().Type())
+
+	call, err := conn.send(serviceMethod, args, fresh.Interface())
+	if err != nil {
+		c.drop(conn)
+		return err
+	}
+
+	select {
+	case <-call.Done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if call.Error != nil {
+		if _, ok := call.Error.(rpc.ServerError); !ok {
+			c.drop(conn)
+		}
+		return call.Error
+	}
+
+	v.Elem().Set(fresh.Elem())
+
+	return nil
+}
+
+// connect returns the current connection and opens one when there is none
+func (c *Client) connect(ctx context.Context) (*conn, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil, ErrClosed
+	}
+	if c.conn != nil {
+		return c.conn, nil
+	}
+
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", c.Addr)
 	if err != nil {
-		log.Fatal("error:", err)
+		return nil, fmt.Errorf("client: dialing %v: %w", c.Addr, err)
 	}
+	c.conn = &conn{Client: rpc.NewClient(nc)}
+
+	return c.conn, nil
+}
+
+// drop closes a connection which failed, unless it has already been replaced
+func (c *Client) drop(conn *conn) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.conn == conn {
+		c.conn.close()
+		c.conn = nil
+	}
+}
+
+// Close closes the connection, later calls return ErrClosed
+func (c *Client) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.closed = true
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.close()
+	c.conn = nil
 
-	return reply
+	return err
 }
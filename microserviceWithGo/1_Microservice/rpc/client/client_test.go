@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/rpc"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/registry"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/server"
+)
+
+type SlowHandler struct{}
+
+// sleeps counts the calls of SlowHandler.Sleep
+var sleeps int32
+
+func (h *SlowHandler) Sleep(d time.Duration, reply *contract.HelloWorldResponse) error {
+	atomic.AddInt32(&sleeps, 1)
+	time.Sleep(d)
+	reply.Message = "awake"
+	return nil
+}
+
+// testServer serves the handlers and can drop its connections
+type testServer struct {
+	listener net.Listener
+	mutex    sync.Mutex
+	conns    []net.Conn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	s := rpc.NewServer()
+	r, err := registry.New(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.RegisterName(contract.HelloWorldService, &server.HelloWorldHandler{})
+	r.Register(&SlowHandler{})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := &testServer{listener: l}
+	t.Cleanup(func() {
+		l.Close()
+		ts.dropConns()
+	})
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+
+			ts.mutex.Lock()
+			ts.conns = append(ts.conns, conn)
+			ts.mutex.Unlock()
+
+			go s.ServeConn(conn)
+		}
+	}()
+
+	return ts
+}
+
+func (s *testServer) dropConns() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, c := range s.conns {
+		c.Close()
+	}
+	s.conns = nil
+}
+
+func TestCallsHelloWorld(t *testing.T) {
+	ts := newTestServer(t)
+	c, err := Dial(ts.listener.Addr().String())
+	assert.Nil(t, err)
+	defer c.Close()
+
+	reply, err := c.HelloWorld(context.Background(), &contract.HelloWorldRequest{Name: "World"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello World", reply.Message)
+}
+
+func TestDialReturnsError(t *testing.T) {
+	l, _ := net.Listen("tcp", "127.0.0.1:0")
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err := Dial(addr)
+
+	assert.NotNil(t, err)
+}
+
+func TestReturnsServerErrors(t *testing.T) {
+	ts := newTestServer(t)
+	c := &Client{Addr: ts.listener.Addr().String()}
+	defer c.Close()
+
+	err := c.Call(context.Background(), "HelloWorldHandler.Missing", &contract.HelloWorldRequest{}, &contract.HelloWorldResponse{})
+
+	_, ok := err.(rpc.ServerError)
+	assert.True(t, ok)
+}
+
+func TestCallTimesOut(t *testing.T) {
+	ts := newTestServer(t)
+	c := &Client{Addr: ts.listener.Addr().String(), Timeout: 10 * time.Millisecond}
+	defer c.Close()
+
+	reply := &contract.HelloWorldResponse{}
+	err := c.Call(context.Background(), "SlowHandler.Sleep", 100*time.Millisecond, reply)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// the late reply is thrown away
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, "", reply.Message)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err = c.Call(ctx, "SlowHandler.Sleep", time.Millisecond, reply)
+	assert.Nil(t, err)
+	assert.Equal(t, "awake", reply.Message)
+}
+
+func TestReconnectsAfterConnectionDrops(t *testing.T) {
+	ts := newTestServer(t)
+	c := &Client{Addr: ts.listener.Addr().String()}
+	defer c.Close()
+
+	_, err := c.HelloWorld(context.Background(), &contract.HelloWorldRequest{Name: "World"})
+	assert.Nil(t, err)
+
+	ts.dropConns()
+	// wait until the client has noticed the drop
+	time.Sleep(10 * time.Millisecond)
+
+	reply, err := c.HelloWorld(context.Background(), &contract.HelloWorldRequest{Name: "again"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello again", reply.Message)
+}
+
+func TestDoesNotRetryCallWhichWasSent(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := Dial(ts.listener.Addr().String())
+	defer c.Close()
+	before := atomic.LoadInt32(&sleeps)
+
+	errs := make(chan error)
+	go func() {
+		errs <- c.Call(context.Background(), "SlowHandler.Sleep", 50*time.Millisecond, &contract.HelloWorldResponse{})
+	}()
+	time.Sleep(10 * time.Millisecond)
+	c.mutex.Lock()
+	conn := c.conn
+	c.mutex.Unlock()
+	c.drop(conn)
+
+	assert.NotNil(t, <-errs)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&sleeps)-before)
+}
+
+func TestRetriesCallWhichWasNotSent(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := Dial(ts.listener.Addr().String())
+	defer c.Close()
+
+	// shut the connection down behind the client's back
+	c.mutex.Lock()
+	c.conn.close()
+	c.mutex.Unlock()
+
+	reply, err := c.HelloWorld(context.Background(), &contract.HelloWorldRequest{Name: "again"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Hello again", reply.Message)
+}
+
+func TestClosedClientReturnsErrClosed(t *testing.T) {
+	ts := newTestServer(t)
+	c, _ := Dial(ts.listener.Addr().String())
+	c.Close()
+
+	_, err := c.HelloWorld(context.Background(), &contract.HelloWorldRequest{})
+
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestListsServices(t *testing.T) {
+	ts := newTestServer(t)
+	c := &Client{Addr: ts.listener.Addr().String()}
+	defer c.Close()
+
+	services, err := c.Services(context.Background())
+
+	assert.Nil(t, err)
+	names := []string{}
+	for _, s := range services {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{contract.HelloWorldService, registry.ServiceName, "SlowHandler"}, names)
+}
@@ -0,0 +1,116 @@
+// Command gen writes contract_gen.go, a typed Client method for every method
+// of the contract package. A method is a constant named <Name>Method with
+// the types <Name>Request and <Name>Response next to it.
+//
+// It is run by go generate in the client package.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+)
+
+const (
+	contractDir  = "../contract"
+	contractPath = "github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	output       = "contract_gen.go"
+)
+
+func main() {
+	methods, err := contractMethods(contractDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	src, err := generate(methods)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(output, src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// contractMethods returns the names of the methods declared in dir, ordered
+// by name
+func contractMethods(dir string) ([]string, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	consts := map[string]bool{}
+	types := map[string]bool{}
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+
+				for _, spec := range gd.Specs {
+					switch s := spec.(type) {
+					case *ast.ValueSpec:
+						if gd.Tok == token.CONST {
+							for _, name := range s.Names {
+								consts[name.Name] = true
+							}
+						}
+					case *ast.TypeSpec:
+						types[s.Name.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	var methods []string
+	for c := range consts {
+		name := strings.TrimSuffix(c, "Method")
+		if name == c || !ast.IsExported(name) {
+			continue
+		}
+		if !types[name+"Request"] || !types[name+"Response"] {
+			return nil, fmt.Errorf("gen: %v has no %vRequest and %vResponse types", c, name, name)
+		}
+		methods = append(methods, name)
+	}
+	sort.Strings(methods)
+
+	return methods, nil
+}
+
+func generate(methods []string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "// Code generated by gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package client\n\n")
+	fmt.Fprintf(&buf, "import (\n\t\"context\"\n\n\t%q\n)\n", contractPath)
+
+	for _, m := range methods {
+		fmt.Fprintf(&buf, `
+// %[1]v calls contract.%[1]vMethod
+func (c *Client) %[1]v(ctx context.Context, args *contract.%[1]vRequest) (*contract.%[1]vResponse, error) {
+	reply := &contract.%[1]vResponse{}
+	if err := c.Call(ctx, contract.%[1]vMethod, args, reply); err != nil {
+		return nil, err
+	}
+
+	return reply, nil
+}
+`, m)
+	}
+
+	return format.Source(buf.Bytes())
+}
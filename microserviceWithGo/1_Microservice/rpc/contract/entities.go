@@ -1,5 +1,12 @@
 package contract
 
+// HelloWorldService is the name the HelloWorld handler is registered with,
+// HelloWorldMethod the method clients call
+const (
+	HelloWorldService = "HelloWorldHandler"
+	HelloWorldMethod  = HelloWorldService + ".HelloWorld"
+)
+
 type HelloWorldRequest struct {
 	Name string
 }
@@ -0,0 +1,243 @@
+// Package registry records the services registered on a net/rpc server so
+// that clients can discover them. The services are listed by the
+// Registry.List RPC and as JSON over HTTP.
+//
+// net/rpc skips methods which do not follow its rules without telling
+// anyone, the registry rejects such services instead so that mistakes show
+// up at startup.
+package registry
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"net/http"
+	"net/rpc"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ServiceName is the name the registry itself is served under
+const ServiceName = "Registry"
+
+// ListMethod is the method clients call to list the services
+const ListMethod = ServiceName + ".List"
+
+var typeOfError = reflect.TypeOf((*error)(nil)).Elem()
+
+// Field is a field of an argument or reply struct
+type Field struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Type describes an argument or reply type, Fields are the exported fields
+// of structs
+type Type struct {
+	Name   string  `json:"name"`
+	Fields []Field `json:"fields,omitempty"`
+}
+
+// Method is a method clients can call as Service.Method
+type Method struct {
+	Name  string `json:"name"`
+	Args  Type   `json:"args"`
+	Reply Type   `json:"reply"`
+}
+
+// Service is a registered receiver and its methods sorted by name
+type Service struct {
+	Name    string   `json:"name"`
+	Methods []Method `json:"methods"`
+}
+
+// ListRequest is the argument of Registry.List
+type ListRequest struct{}
+
+// ListResponse is the reply of Registry.List, Services are sorted by name
+type ListResponse struct {
+	Services []Service `json:"services"`
+}
+
+// Registry registers services on an rpc.Server and records them
+type Registry struct {
+	server *rpc.Server
+
+	mutex    sync.RWMutex
+	services map[string]Service
+}
+
+// New creates a Registry for s and serves Registry.List on it
+func New(s *rpc.Server) (*Registry, error) {
+	r := &Registry{server: s, services: map[string]Service{}}
+	if err := r.RegisterName(ServiceName, &listHandler{registry: r}); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Register validates and registers rcvr under the name of its type
+func (r *Registry) Register(rcvr interface{}) error {
+	return r.RegisterName(reflect.Indirect(reflect.ValueOf(rcvr)).Type().Name(), rcvr)
+}
+
+// RegisterName validates rcvr and registers it under name. An error is
+// returned when any exported method does not follow the net/rpc rules.
+func (r *Registry) RegisterName(name string, rcvr interface{}) error {
+	service, err := Describe(name, rcvr)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, ok := r.services[name]; ok {
+		return fmt.Errorf("registry: service %v already registered", name)
+	}
+	if err := r.server.RegisterName(name, rcvr); err != nil {
+		return err
+	}
+	r.services[name] = service
+
+	return nil
+}
+
+// List returns the registered services sorted by name
+func (r *Registry) List() []Service {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	services := make([]Service, 0, len(r.services))
+	for _, s := range r.services {
+		services = append(services, s)
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+	return services
+}
+
+// ServeHTTP writes the services as JSON
+func (r *Registry) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		rw.Header().Set("Allow", "GET, HEAD")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(rw).Encode(ListResponse{Services: r.List()})
+}
+
+// listHandler serves Registry.List, it is separate from Registry so that
+// the methods of Registry are not offered over RPC
+type listHandler struct {
+	registry *Registry
+}
+
+func (h *listHandler) List(args *ListRequest, reply *ListResponse) error {
+	reply.Services = h.registry.List()
+	return nil
+}
+
+// Describe returns the service rcvr offers under name. Every exported method
+// must have the form
+//
+//	func (t *T) MethodName(args T1, reply *T2) error
+//
+// where T1 and T2 are exported or builtin, the errors of all methods which
+// do not are returned together.
+func Describe(name string, rcvr interface{}) (Service, error) {
+	if rcvr == nil {
+		return Service{}, errors.New("registry: receiver is nil")
+	}
+	if !token.IsExported(name) {
+		return Service{}, fmt.Errorf("registry: service name %q is not exported", name)
+	}
+
+	t := reflect.TypeOf(rcvr)
+	service := Service{Name: name}
+	var problems []string
+
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if err := checkMethod(m); err != nil {
+			problems = append(problems, fmt.Sprintf("%v.%v %v", name, m.Name, err))
+			continue
+		}
+
+		service.Methods = append(service.Methods, Method{
+			Name:  m.Name,
+			Args:  describeType(m.Type.In(1)),
+			Reply: describeType(m.Type.In(2)),
+		})
+	}
+
+	if len(problems) > 0 {
+		return Service{}, fmt.Errorf("registry: %v", strings.Join(problems, "; "))
+	}
+	if len(service.Methods) == 0 {
+		return Service{}, fmt.Errorf("registry: %v has no exported methods, is the receiver a pointer?", name)
+	}
+
+	return service, nil
+}
+
+// checkMethod applies the rules of net/rpc to an exported method
+func checkMethod(m reflect.Method) error {
+	mt := m.Type
+	if mt.NumIn() != 3 {
+		return fmt.Errorf("has %v arguments, needs args and reply", mt.NumIn()-1)
+	}
+	if args := mt.In(1); !isExportedOrBuiltin(args) {
+		return fmt.Errorf("args type %v is not exported", args)
+	}
+
+	reply := mt.In(2)
+	if reply.Kind() != reflect.Ptr {
+		return fmt.Errorf("reply type %v is not a pointer", reply)
+	}
+	if !isExportedOrBuiltin(reply) {
+		return fmt.Errorf("reply type %v is not exported", reply)
+	}
+
+	if mt.NumOut() != 1 || mt.Out(0) != typeOfError {
+		return errors.New("must return only an error")
+	}
+
+	return nil
+}
+
+func isExportedOrBuiltin(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return token.IsExported(t.Name()) || t.PkgPath() == ""
+}
+
+func describeType(t reflect.Type) Type {
+	d := Type{Name: t.String()}
+
+	s := t
+	for s.Kind() == reflect.Ptr {
+		s = s.Elem()
+	}
+	if s.Kind() != reflect.Struct {
+		return d
+	}
+
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		if f.PkgPath != "" {
+			continue // unexported, not sent by gob or JSON
+		}
+		d.Fields = append(d.Fields, Field{Name: f.Name, Type: f.Type.String()})
+	}
+
+	return d
+}
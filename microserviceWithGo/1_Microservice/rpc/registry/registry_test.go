@@ -0,0 +1,119 @@
+package registry
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+)
+
+type HelloWorldHandler struct{}
+
+func (h *HelloWorldHandler) HelloWorld(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) error {
+	reply.Message = "Hello " + args.Name
+	return nil
+}
+
+func (h *HelloWorldHandler) Count(args int, reply *int) error {
+	*reply = args + 1
+	return nil
+}
+
+type BrokenHandler struct{}
+
+func (h *BrokenHandler) NoReply(args *contract.HelloWorldRequest) error { return nil }
+
+func (h *BrokenHandler) ValueReply(args *contract.HelloWorldRequest, reply contract.HelloWorldResponse) error {
+	return nil
+}
+
+func (h *BrokenHandler) NoError(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) {
+}
+
+type unexported struct{}
+
+func (h *BrokenHandler) Hidden(args *unexported, reply *contract.HelloWorldResponse) error {
+	return nil
+}
+
+var helloWorld = Service{Name: "HelloWorldHandler", Methods: []Method{
+	{
+		Name:  "Count",
+		Args:  Type{Name: "int"},
+		Reply: Type{Name: "*int"},
+	},
+	{
+		Name:  "HelloWorld",
+		Args:  Type{Name: "*contract.HelloWorldRequest", Fields: []Field{{Name: "Name", Type: "string"}}},
+		Reply: Type{Name: "*contract.HelloWorldResponse", Fields: []Field{{Name: "Message", Type: "string"}}},
+	},
+}}
+
+func TestDescribesService(t *testing.T) {
+	s, err := Describe("HelloWorldHandler", &HelloWorldHandler{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, helloWorld, s)
+}
+
+func TestRejectsInvalidMethods(t *testing.T) {
+	_, err := Describe("BrokenHandler", &BrokenHandler{})
+
+	assert.EqualError(t, err, "registry: "+
+		"BrokenHandler.Hidden args type *registry.unexported is not exported; "+
+		"BrokenHandler.NoError must return only an error; "+
+		"BrokenHandler.NoReply has 1 arguments, needs args and reply; "+
+		"BrokenHandler.ValueReply reply type contract.HelloWorldResponse is not a pointer")
+}
+
+func TestRejectsReceiverWithoutMethods(t *testing.T) {
+	_, err := Describe("HelloWorldHandler", HelloWorldHandler{})
+	assert.NotNil(t, err)
+
+	_, err = Describe("hello", &HelloWorldHandler{})
+	assert.NotNil(t, err)
+}
+
+func TestListsServicesOverRPC(t *testing.T) {
+	s := rpc.NewServer()
+	r, err := New(s)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Register(&HelloWorldHandler{}))
+	assert.NotNil(t, r.Register(&HelloWorldHandler{}))
+	assert.NotNil(t, r.Register(&BrokenHandler{}))
+
+	server, conn := net.Pipe()
+	go s.ServeConn(server)
+	c := rpc.NewClient(conn)
+	defer c.Close()
+
+	var reply ListResponse
+	assert.Nil(t, c.Call(ListMethod, &ListRequest{}, &reply))
+	assert.Equal(t, 2, len(reply.Services))
+	assert.Equal(t, helloWorld, reply.Services[0])
+	assert.Equal(t, ServiceName, reply.Services[1].Name)
+
+	// the registered handler is served
+	var hello contract.HelloWorldResponse
+	assert.Nil(t, c.Call("HelloWorldHandler.HelloWorld", &contract.HelloWorldRequest{Name: "World"}, &hello))
+	assert.Equal(t, "Hello World", hello.Message)
+}
+
+func TestServesJSONPage(t *testing.T) {
+	r, _ := New(rpc.NewServer())
+	r.Register(&HelloWorldHandler{})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var page ListResponse
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&page))
+	assert.Equal(t, helloWorld, page.Services[0])
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"log"
 	"testing"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/client"
@@ -11,7 +12,10 @@ func BenchmarkDial(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		c := client.CreateClient()
+		c, err := client.CreateClient()
+		if err != nil {
+			b.Fatal(err)
+		}
 		c.Close()
 	}
 }
@@ -20,10 +24,15 @@ func BenchmarkDial(b *testing.B) {
 func BenchmarkHelloWorldHandler(b *testing.B) {
 	b.ResetTimer()
 
-	c := client.CreateClient()
+	c, err := client.CreateClient()
+	if err != nil {
+		b.Fatal(err)
+	}
 
 	for i := 0; i < b.N; i++ {
-		client.PerformRequest(c)
+		if _, err := client.PerformRequest(c); err != nil {
+			b.Fatal(err)
+		}
 	}
 
 	c.Close()
@@ -32,5 +41,9 @@ func BenchmarkHelloWorldHandler(b *testing.B) {
 
 func init() {
 	// start the server
-	go server.StartServer()
+	go func() {
+		if err := server.StartServer(); err != nil {
+			log.Fatal(err)
+		}
+	}()
 }
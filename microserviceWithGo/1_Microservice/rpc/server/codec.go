@@ -0,0 +1,86 @@
+package server
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"log"
+	"net"
+	"net/rpc"
+)
+
+// serverCodec is the gob codec of net/rpc with read deadlines, the idle
+// timeout applies while waiting for the next request and the read timeout
+// while reading its arguments
+type serverCodec struct {
+	server *Server
+	conn   net.Conn
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+	// waiting is set while waiting for the next request header and inFlight
+	// counts the calls whose response has not been written, the connection
+	// is idle when it is waiting without calls in flight. Both are guarded
+	// by server.mutex.
+	waiting  bool
+	inFlight int
+}
+
+func newServerCodec(s *Server, conn net.Conn) *serverCodec {
+	buf := bufio.NewWriter(conn)
+	return &serverCodec{
+		server: s,
+		conn:   conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(buf),
+		encBuf: buf,
+	}
+}
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	if !c.server.waitForHeader(c) {
+		return io.EOF
+	}
+
+	return c.dec.Decode(r)
+}
+
+func (c *serverCodec) ReadRequestBody(body interface{}) error {
+	// the header has been read, the call is finished even while stopping
+	c.server.readBody(c)
+
+	return c.dec.Decode(body)
+}
+
+// WriteResponse writes the response of a call, net/rpc calls it once for
+// every ReadRequestBody
+func (c *serverCodec) WriteResponse(r *rpc.Response, body interface{}) (err error) {
+	defer c.server.finishCall(c)
+
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil {
+			log.Println("rpc: gob error encoding response:", err)
+			c.Close()
+		}
+		return
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil {
+			log.Println("rpc: gob error encoding body:", err)
+			c.Close()
+		}
+		return
+	}
+
+	return c.encBuf.Flush()
+}
+
+func (c *serverCodec) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	return c.conn.Close()
+}
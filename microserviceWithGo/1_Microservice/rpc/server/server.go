@@ -1,41 +1,369 @@
 package server
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"net/rpc"
+	"sync"
+	"time"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/registry"
 )
 
 const port = 1234
 
+// RegistryPath is where ListenAndServe mounts the JSON page of the registry
+// on the mux it is given
+const RegistryPath = "/registry"
+
+// DefaultDrainTimeout is how long Stop waits for active calls when
+// DrainTimeout is not set
+const DefaultDrainTimeout = 30 * time.Second
+
+const (
+	// minAcceptDelay and maxAcceptDelay bound the wait after temporary
+	// Accept errors, e.g. when the process runs out of file descriptors
+	minAcceptDelay = 5 * time.Millisecond
+	maxAcceptDelay = time.Second
+)
+
+// ErrServerClosed is returned by Serve after Stop
+var ErrServerClosed = errors.New("server: closed")
+
 func main() {
 	log.Printf("Server starting on port %v\n", port)
-	StartServer()
+	if err := StartServer(); err != nil {
+		log.Fatal(err)
+	}
 }
 
-func StartServer() {
+// StartServer serves the handlers on the default port without the registry
+// page
+func StartServer() error {
+	return ListenAndServe(fmt.Sprintf(":%v", port), nil)
+}
+
+// ListenAndServe registers the handlers on rpc.DefaultServer and serves them
+// on addr. When mux is not nil the list of services is mounted on it at
+// RegistryPath, serving mux is up to the caller.
+func ListenAndServe(addr string, mux *http.ServeMux) error {
 	// 핸들러의 새 인스턴스를 만든 다음, 기본 RPC 서버에 등록한다.
+	// 레지스트리는 메서드 시그니처를 검사하고 등록된 서비스 목록을 제공한다.
+	reg, err := registry.New(rpc.DefaultServer)
+	if err != nil {
+		return err
+	}
 	helloWorld := &HelloWorldHandler{}
-	rpc.Register(helloWorld)
+	if err := reg.RegisterName(contract.HelloWorldService, helloWorld); err != nil {
+		return err
+	}
+
+	// 서비스 목록을 HTTP JSON 페이지로도 제공
+	if mux != nil {
+		mux.Handle(RegistryPath, reg)
+	}
 
 	// func Listen(network, address string) (Listener, error)
 	// Listener 인터페이스 구현
 	// Accept() : 리스너의 다음 연결을 기다리고 있다가 그것을 리턴한다.
 	// Close() : 리스너를 닫는다. 연결을 기다리고 있던 Accept 동작에서 빠져나와 에러를 리턴한다.
 	// Addr() : 리스너의 네트워크 주소를 리턴한다.
-	l, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+	l, err := net.Listen("tcp", addr)
 	if err != nil {
-		log.Fatal(fmt.Sprintf("Unable to listen on given port: %s", err))
+		return fmt.Errorf("server: unable to listen on %v: %w", addr, err)
+	}
+
+	s := &Server{MaxConns: 1000, IdleTimeout: 5 * time.Minute, ReadTimeout: 10 * time.Second}
+	if err := s.Serve(l); err != ErrServerClosed {
+		return err
+	}
+
+	return nil
+}
+
+// Server accepts net/rpc connections with the gob codec. The zero value
+// serves rpc.DefaultServer without limits.
+type Server struct {
+	// RPC is the server whose handlers are called, defaults to
+	// rpc.DefaultServer.
+	RPC *rpc.Server
+	// TLSConfig enables TLS on the accepted connections when set.
+	TLSConfig *tls.Config
+	// MaxConns is the most connections served at once, further connections
+	// wait in the listen backlog. 0 means no limit.
+	MaxConns int
+	// IdleTimeout closes connections which do not send a request for this
+	// long. 0 means no limit.
+	IdleTimeout time.Duration
+	// ReadTimeout is how long reading the arguments of a call may take once
+	// its header has arrived. 0 means no limit.
+	ReadTimeout time.Duration
+	// DrainTimeout is how long Stop waits for active calls, defaults to
+	// DefaultDrainTimeout.
+	DrainTimeout time.Duration
+
+	mutex     sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[*serverCodec]struct{}
+	slots     chan struct{}
+	stopping  bool
+	done      chan struct{}
+	wg        sync.WaitGroup
+}
+
+// Serve accepts connections on l until Stop is called. Temporary Accept
+// errors are retried with a growing delay, other errors are returned.
+func (s *Server) Serve(l net.Listener) error {
+	if s.TLSConfig != nil {
+		l = tls.NewListener(l, s.TLSConfig)
 	}
-	defer l.Close()
 
-	for { // 무한루프
-		conn, _ := l.Accept()  // 연결이 이루어 질 때까지 대기
-		go rpc.ServeConn(conn) // 클라이언트가 완료될 때까지 대기(고루틴을 쓰기 때문에 즉시 다음 연결 처리 가능)
+	if !s.addListener(l) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.removeListener(l)
+
+	var delay time.Duration
+	for {
+		// 연결 수 제한: 빈 자리가 생길 때까지 Accept 를 미룸
+		if s.slots != nil {
+			select {
+			case s.slots <- struct{}{}:
+			case <-s.done:
+				return ErrServerClosed
+			}
+		}
+
+		conn, err := l.Accept()
+		if err != nil {
+			s.release()
+			if s.isStopping() {
+				return ErrServerClosed
+			}
+
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if delay == 0 {
+					delay = minAcceptDelay
+				} else if delay *= 2; delay > maxAcceptDelay {
+					delay = maxAcceptDelay
+				}
+				log.Printf("rpc: accept error: %v; retrying in %v", err, delay)
+
+				select {
+				case <-time.After(delay):
+				case <-s.done:
+					return ErrServerClosed
+				}
+				continue
+			}
+
+			return err
+		}
+		delay = 0
+
+		codec := newServerCodec(s, conn)
+		if !s.track(codec) {
+			s.release()
+			conn.Close()
+			return ErrServerClosed
+		}
+
+		go func() {
+			defer s.wg.Done()
+			defer s.release()
+			defer s.untrack(codec)
+
+			s.rpc().ServeCodec(codec)
+		}()
+	}
+}
+
+// Stop stops accepting connections and waits for the active calls to
+// finish, connections are closed once their calls have been answered. After
+// DrainTimeout the remaining connections are closed and an error is
+// returned.
+func (s *Server) Stop() error {
+	s.mutex.Lock()
+	s.init()
+	if !s.stopping {
+		s.stopping = true
+		close(s.done)
+	}
+	for l := range s.listeners {
+		l.Close()
+	}
+	// 진행 중인 호출 없이 다음 요청을 기다리는 연결만 깨워서 진행 중인 호출은 끝나도록 함
+	for c := range s.conns {
+		if c.waiting && c.inFlight == 0 {
+			c.conn.SetReadDeadline(time.Now())
+		}
+	}
+	s.mutex.Unlock()
+
+	timeout := s.DrainTimeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(timeout):
+	}
+
+	s.mutex.Lock()
+	for c := range s.conns {
+		c.conn.Close()
+	}
+	s.mutex.Unlock()
+
+	return fmt.Errorf("server: calls still active after %v", timeout)
+}
+
+func (s *Server) rpc() *rpc.Server {
+	if s.RPC != nil {
+		return s.RPC
+	}
+
+	return rpc.DefaultServer
+}
+
+// init creates the state of the zero value, s.mutex must be held
+func (s *Server) init() {
+	if s.done != nil {
+		return
+	}
+
+	s.listeners = map[net.Listener]struct{}{}
+	s.conns = map[*serverCodec]struct{}{}
+	s.done = make(chan struct{})
+	if s.MaxConns > 0 {
+		s.slots = make(chan struct{}, s.MaxConns)
+	}
+}
+
+func (s *Server) addListener(l net.Listener) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.init()
+	if s.stopping {
+		return false
+	}
+	s.listeners[l] = struct{}{}
+
+	return true
+}
+
+func (s *Server) removeListener(l net.Listener) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.listeners, l)
+}
+
+func (s *Server) track(c *serverCodec) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopping {
+		return false
+	}
+	s.conns[c] = struct{}{}
+	s.wg.Add(1)
+
+	return true
+}
+
+func (s *Server) untrack(c *serverCodec) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.conns, c)
+}
+
+func (s *Server) release() {
+	if s.slots != nil {
+		<-s.slots
+	}
+}
+
+func (s *Server) isStopping() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.stopping
+}
+
+// waitForHeader marks c as waiting for the next request header, false when
+// the server is stopping and no more requests should be read. The idle
+// timeout only applies while no calls are in flight.
+func (s *Server) waitForHeader(c *serverCodec) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.stopping {
+		return false
+	}
+	c.waiting = true
+	if c.inFlight == 0 {
+		setReadDeadline(c.conn, s.IdleTimeout)
+	} else {
+		setReadDeadline(c.conn, 0)
+	}
+
+	return true
+}
+
+// readBody counts the call whose arguments are read as in flight, Stop no
+// longer interrupts c until it has been answered
+func (s *Server) readBody(c *serverCodec) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c.waiting = false
+	c.inFlight++
+	setReadDeadline(c.conn, s.ReadTimeout)
+}
+
+// finishCall is called once the response of a call has been written, when
+// it was the last call in flight of a waiting connection the idle timeout
+// starts, or the connection is woken up if the server is stopping
+func (s *Server) finishCall(c *serverCodec) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c.inFlight--
+	if c.inFlight > 0 || !c.waiting {
+		return
+	}
+	if s.stopping {
+		c.conn.SetReadDeadline(time.Now())
+	} else {
+		setReadDeadline(c.conn, s.IdleTimeout)
+	}
+}
+
+// setReadDeadline sets the deadline for the next read of conn, no deadline
+// when timeout is 0
+func setReadDeadline(conn net.Conn, timeout time.Duration) {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
 	}
+	conn.SetReadDeadline(deadline)
 }
 
 type HelloWorldHandler struct{}
@@ -0,0 +1,274 @@
+package server
+
+import (
+	"crypto/tls"
+	"encoding/gob"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/rpc"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/tlsconfig/testca"
+)
+
+type SlowHandler struct{}
+
+func (h *SlowHandler) Sleep(d time.Duration, reply *string) error {
+	time.Sleep(d)
+	*reply = "awake"
+	return nil
+}
+
+func newRPC() *rpc.Server {
+	r := rpc.NewServer()
+	r.RegisterName(contract.HelloWorldService, &HelloWorldHandler{})
+	r.Register(&SlowHandler{})
+
+	return r
+}
+
+func setupServer(t *testing.T, s *Server) (string, chan error) {
+	if s.RPC == nil {
+		s.RPC = newRPC()
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(l) }()
+	t.Cleanup(func() { s.Stop() })
+
+	return l.Addr().String(), served
+}
+
+func hello(c *rpc.Client) error {
+	var reply contract.HelloWorldResponse
+	return c.Call(contract.HelloWorldMethod, &contract.HelloWorldRequest{Name: "World"}, &reply)
+}
+
+func TestServesCalls(t *testing.T) {
+	addr, _ := setupServer(t, &Server{})
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	assert.Nil(t, hello(c))
+}
+
+func TestStopReturnsFromServe(t *testing.T) {
+	s := &Server{}
+	_, served := setupServer(t, s)
+
+	time.Sleep(10 * time.Millisecond)
+	assert.Nil(t, s.Stop())
+	assert.Equal(t, ErrServerClosed, <-served)
+}
+
+func TestStopDrainsActiveCalls(t *testing.T) {
+	s := &Server{}
+	addr, served := setupServer(t, s)
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	var reply string
+	call := c.Go("SlowHandler.Sleep", 100*time.Millisecond, &reply, nil)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.Nil(t, s.Stop())
+	assert.Equal(t, ErrServerClosed, <-served)
+
+	<-call.Done
+	assert.Nil(t, call.Error)
+	assert.Equal(t, "awake", reply)
+
+	// the connection is closed once the call is answered
+	assert.NotNil(t, hello(c))
+}
+
+func TestStopLetsBodyReadsFinish(t *testing.T) {
+	s := &Server{}
+	addr, _ := setupServer(t, s)
+
+	conn, err := net.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// send the header of a call and stop the server before its body arrives
+	enc := gob.NewEncoder(conn)
+	assert.Nil(t, enc.Encode(&rpc.Request{ServiceMethod: contract.HelloWorldMethod, Seq: 1}))
+	time.Sleep(20 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- s.Stop() }()
+	time.Sleep(20 * time.Millisecond)
+	assert.Nil(t, enc.Encode(&contract.HelloWorldRequest{Name: "World"}))
+
+	dec := gob.NewDecoder(conn)
+	var resp rpc.Response
+	assert.Nil(t, dec.Decode(&resp))
+	assert.Equal(t, "", resp.Error)
+	var reply contract.HelloWorldResponse
+	assert.Nil(t, dec.Decode(&reply))
+	assert.Equal(t, "Hello World", reply.Message)
+
+	assert.Nil(t, <-stopped)
+}
+
+func TestStopGivesUpAfterDrainTimeout(t *testing.T) {
+	s := &Server{DrainTimeout: 20 * time.Millisecond}
+	addr, _ := setupServer(t, s)
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	call := c.Go("SlowHandler.Sleep", time.Second, new(string), nil)
+	time.Sleep(20 * time.Millisecond)
+
+	assert.NotNil(t, s.Stop())
+	<-call.Done
+	assert.NotNil(t, call.Error)
+}
+
+func TestLimitsConnections(t *testing.T) {
+	addr, _ := setupServer(t, &Server{MaxConns: 1})
+
+	first, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	assert.Nil(t, hello(first))
+
+	// the second connection waits in the backlog until the first is closed
+	second, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer second.Close()
+
+	call := second.Go(contract.HelloWorldMethod, &contract.HelloWorldRequest{}, &contract.HelloWorldResponse{}, nil)
+	select {
+	case <-call.Done:
+		t.Fatal("second connection was served")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	first.Close()
+	select {
+	case <-call.Done:
+		assert.Nil(t, call.Error)
+	case <-time.After(time.Second):
+		t.Fatal("second connection was not served")
+	}
+}
+
+func TestClosesIdleConnections(t *testing.T) {
+	addr, _ := setupServer(t, &Server{IdleTimeout: 20 * time.Millisecond})
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.Nil(t, hello(c))
+
+	time.Sleep(60 * time.Millisecond)
+	assert.NotNil(t, hello(c))
+}
+
+func TestKeepsConnectionsWithCallsInFlight(t *testing.T) {
+	addr, _ := setupServer(t, &Server{IdleTimeout: 30 * time.Millisecond})
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	var reply string
+	assert.Nil(t, c.Call("SlowHandler.Sleep", 100*time.Millisecond, &reply))
+	assert.Equal(t, "awake", reply)
+	assert.Nil(t, hello(c))
+}
+
+func TestServesTLS(t *testing.T) {
+	ca, err := testca.New("rpc")
+	assert.Nil(t, err)
+	pair, err := ca.Issue("rpc", "localhost")
+	assert.Nil(t, err)
+	cert, err := pair.TLSCertificate()
+	assert.Nil(t, err)
+
+	addr, _ := setupServer(t, &Server{TLSConfig: &tls.Config{Certificates: []tls.Certificate{cert}}})
+
+	conn, err := tls.Dial("tcp", addr, &tls.Config{RootCAs: ca.Pool(), ServerName: "localhost"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := rpc.NewClient(conn)
+	defer c.Close()
+
+	assert.Nil(t, hello(c))
+}
+
+// flakyListener fails Accept with temporary errors before accepting
+type flakyListener struct {
+	net.Listener
+	failures int32
+}
+
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "too many open files" }
+func (temporaryError) Timeout() bool   { return false }
+func (temporaryError) Temporary() bool { return true }
+
+func (l *flakyListener) Accept() (net.Conn, error) {
+	if atomic.AddInt32(&l.failures, -1) >= 0 {
+		return nil, temporaryError{}
+	}
+
+	return l.Listener.Accept()
+}
+
+func TestRetriesTemporaryAcceptErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	s := &Server{RPC: newRPC()}
+	defer s.Stop()
+	go s.Serve(&flakyListener{Listener: l, failures: 3})
+
+	c, err := rpc.Dial("tcp", l.Addr().String())
+	assert.Nil(t, err)
+	defer c.Close()
+	assert.Nil(t, hello(c))
+}
+
+func TestReturnsPermanentAcceptErrors(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	l.Close()
+
+	err = (&Server{}).Serve(l)
+
+	assert.NotNil(t, err)
+	assert.False(t, errors.Is(err, ErrServerClosed))
+}
+
+func TestListenAndServeMountsRegistryAndReturnsErrors(t *testing.T) {
+	mux := http.NewServeMux()
+
+	err := ListenAndServe("127.0.0.1:-1", mux)
+	assert.NotNil(t, err)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, RegistryPath, nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), contract.HelloWorldService)
+}
@@ -3,22 +3,37 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_http_json/contract"
 )
 
-func PerformRequest() contract.HelloWorldResponse {
-	r, _ := http.Post(
+func PerformRequest() (contract.HelloWorldResponse, error) {
+	r, err := http.Post(
 		"http://localhost:1234",
 		"application/json",
-		bytes.NewBuffer([]byte(`{"id": 1, "method": "HelloWorldHandler.HelloWorld", "params": [{"name":"World"}]}`)),
+		bytes.NewBuffer([]byte(`{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": {"name":"World"}}`)),
 	)
+	if err != nil {
+		return contract.HelloWorldResponse{}, err
+	}
 	defer r.Body.Close()
 
-	decoder := json.NewDecoder(r.Body)
-	var response contract.HelloWorldResponse
-	decoder.Decode(&response)
+	// JSON-RPC 2.0 응답은 result 또는 error 를 감싸서 보냄
+	var response struct {
+		Result contract.HelloWorldResponse `json:"result"`
+		Error  *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		return contract.HelloWorldResponse{}, err
+	}
+	if response.Error != nil {
+		return contract.HelloWorldResponse{}, fmt.Errorf("jsonrpc: %v (%v)", response.Error.Message, response.Error.Code)
+	}
 
-	return response
+	return response.Result, nil
 }
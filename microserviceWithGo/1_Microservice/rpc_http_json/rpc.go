@@ -3,7 +3,8 @@ package main
 import "github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_http_json/server"
 
 // To execute a request with this server run the below command on your command line
-// curl -X POST -H "Content-Type: application/json" -d '{"id": 1, "method": "HelloWorldHandler.HelloWorld", "params": [{"name":"World"}]}' http://localhost:1234
+// curl -X POST -H "Content-Type: application/json" -d '{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": {"name":"World"}}' http://localhost:1234
+// Several calls can be sent at once as an array, calls without an id get no response.
 func main() {
 	server.StartServer()
 }
@@ -11,8 +11,9 @@ func BenchmarkHelloWorldHandlerJSONRPC(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		_ = client.PerformRequest()
-
+		if _, err := client.PerformRequest(); err != nil {
+			b.Fatal(err)
+		}
 	}
 }
 
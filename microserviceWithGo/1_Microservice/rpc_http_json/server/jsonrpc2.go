@@ -0,0 +1,328 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/rpc"
+	"reflect"
+	"sync"
+)
+
+// Error codes defined by the JSON-RPC 2.0 specification
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+	// ServerError is used for the errors returned by the handlers
+	ServerError = -32000
+)
+
+// maxBodySize is the largest request body, single call or batch
+const maxBodySize = 1 << 20
+
+// maxBatchConcurrency is how many calls of one batch are served at once
+const maxBatchConcurrency = 8
+
+// Error is the error object of a JSON-RPC 2.0 response
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("jsonrpc: %v (%v)", e.Message, e.Code)
+}
+
+type response struct {
+	Version string          `json:"jsonrpc"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+var null = json.RawMessage("null")
+
+var typeOfError = reflect.TypeOf((*error)(nil)).Elem()
+
+// Server is an rpc.Server which remembers the methods registered on it, so
+// that calls of unknown methods are answered with MethodNotFound without
+// reaching net/rpc
+type Server struct {
+	*rpc.Server
+
+	mutex   sync.RWMutex
+	methods map[string]bool
+}
+
+// NewServer returns a Server without any handlers
+func NewServer() *Server {
+	return &Server{Server: rpc.NewServer(), methods: map[string]bool{}}
+}
+
+// Register registers the methods of rcvr under the name of its type, see
+// rpc.Server.Register
+func (s *Server) Register(rcvr interface{}) error {
+	return s.RegisterName(reflect.Indirect(reflect.ValueOf(rcvr)).Type().Name(), rcvr)
+}
+
+// RegisterName registers the methods of rcvr under name, see
+// rpc.Server.RegisterName
+func (s *Server) RegisterName(name string, rcvr interface{}) error {
+	if err := s.Server.RegisterName(name, rcvr); err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	t := reflect.TypeOf(rcvr)
+	for i := 0; i < t.NumMethod(); i++ {
+		if m := t.Method(i); suitable(m) {
+			s.methods[name+"."+m.Name] = true
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) hasMethod(method string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.methods[method]
+}
+
+// suitable reports whether net/rpc serves m, it follows the rules of
+// rpc.Server.Register
+func suitable(m reflect.Method) bool {
+	mt := m.Type
+	if m.PkgPath != "" || mt.NumIn() != 3 || mt.NumOut() != 1 || mt.Out(0) != typeOfError {
+		return false
+	}
+
+	return exportedOrBuiltin(mt.In(1)) && mt.In(2).Kind() == reflect.Ptr && exportedOrBuiltin(mt.In(2))
+}
+
+func exportedOrBuiltin(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	return token.IsExported(t.Name()) || t.PkgPath() == ""
+}
+
+// NewHandler serves JSON-RPC 2.0 requests from POST bodies with the handlers
+// registered on s, methods which were not registered get MethodNotFound. A body is a single request or a batch array, requests
+// without an id are notifications which get no response. When a body holds
+// only notifications the response is 204 No Content.
+func NewHandler(s *Server) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxBodySize+1))
+		if err != nil {
+			http.Error(w, "could not read request body", http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxBodySize {
+			http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		reply, ok := serveBody(s, body)
+		if !ok {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(reply)
+	})
+}
+
+// serveBody serves a single request or a batch and returns the response to
+// send, false when there is none
+func serveBody(s *Server, body []byte) (interface{}, bool) {
+	body = bytes.TrimSpace(body)
+	if !json.Valid(body) {
+		return failed(null, ParseError, "Parse error", nil), true
+	}
+
+	if body[0] != '[' {
+		res := serveCall(s, body)
+		return res, res != nil
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil || len(batch) == 0 {
+		return failed(null, InvalidRequest, "Invalid Request", nil), true
+	}
+
+	// 배치 안의 요청은 maxBatchConcurrency 개까지 동시에 처리하고 응답은 요청 순서대로 보냄
+	results := make([]*response, len(batch))
+	sem := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, raw := range batch {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int, raw json.RawMessage) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = serveCall(s, raw)
+		}(i, raw)
+	}
+	wg.Wait()
+
+	responses := []*response{}
+	for _, res := range results {
+		if res != nil {
+			responses = append(responses, res)
+		}
+	}
+
+	return responses, len(responses) > 0
+}
+
+// serveCall serves one request object, nil is returned for notifications
+func serveCall(s *Server, raw json.RawMessage) *response {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return failed(null, InvalidRequest, "Invalid Request", nil)
+	}
+
+	id, hasID := fields["id"]
+	if !hasID {
+		id = nil
+	} else if !validID(id) {
+		return failed(null, InvalidRequest, "Invalid Request", "id must be a string, number or null")
+	}
+
+	var version, method string
+	if json.Unmarshal(fields["jsonrpc"], &version) != nil || version != "2.0" {
+		return failed(orNull(id), InvalidRequest, "Invalid Request", `jsonrpc must be "2.0"`)
+	}
+	if json.Unmarshal(fields["method"], &method) != nil || method == "" {
+		return failed(orNull(id), InvalidRequest, "Invalid Request", "method must be a string")
+	}
+
+	c := &codec{method: method, params: fields["params"]}
+	if s.hasMethod(method) {
+		s.ServeRequest(c)
+	} else {
+		c.err = &Error{Code: MethodNotFound, Message: "Method not found", Data: method}
+	}
+
+	// 알림(notification)에는 응답하지 않음
+	if !hasID {
+		return nil
+	}
+	if c.err != nil {
+		return &response{Version: "2.0", Error: c.err, ID: id}
+	}
+
+	return &response{Version: "2.0", Result: c.result, ID: id}
+}
+
+func failed(id json.RawMessage, code int, message string, data interface{}) *response {
+	return &response{Version: "2.0", Error: &Error{Code: code, Message: message, Data: data}, ID: id}
+}
+
+func validID(id json.RawMessage) bool {
+	switch id[0] {
+	case '"', 'n', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	default:
+		return false
+	}
+}
+
+func orNull(id json.RawMessage) json.RawMessage {
+	if id == nil {
+		return null
+	}
+
+	return id
+}
+
+// codec is an rpc.ServerCodec for a single JSON-RPC 2.0 call, it keeps the
+// response instead of writing it
+type codec struct {
+	method string
+	params json.RawMessage
+	read   bool
+
+	paramsErr error
+	result    interface{}
+	err       *Error
+}
+
+func (c *codec) ReadRequestHeader(r *rpc.Request) error {
+	if c.read {
+		return io.EOF
+	}
+	c.read = true
+	r.ServiceMethod = c.method
+
+	return nil
+}
+
+// ReadRequestBody decodes the params into the argument of the method, they
+// are either the argument itself or an array holding it
+func (c *codec) ReadRequestBody(x interface{}) error {
+	if x == nil || len(c.params) == 0 {
+		return nil
+	}
+
+	params := c.params
+	if params[0] == '[' {
+		var positional []json.RawMessage
+		if err := json.Unmarshal(params, &positional); err != nil {
+			c.paramsErr = err
+			return err
+		}
+		if len(positional) != 1 {
+			c.paramsErr = errors.New("params must hold exactly one argument")
+			return c.paramsErr
+		}
+		params = positional[0]
+	}
+
+	if err := json.Unmarshal(params, x); err != nil {
+		c.paramsErr = err
+		return err
+	}
+
+	return nil
+}
+
+func (c *codec) WriteResponse(r *rpc.Response, x interface{}) error {
+	switch {
+	case r.Error == "":
+		c.result = x
+	case c.paramsErr != nil:
+		c.err = &Error{Code: InvalidParams, Message: "Invalid params", Data: c.paramsErr.Error()}
+	default:
+		c.err = &Error{Code: ServerError, Message: r.Error}
+	}
+
+	return nil
+}
+
+func (c *codec) Close() error {
+	return nil
+}
@@ -0,0 +1,158 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_http_json/contract"
+)
+
+type FailingHandler struct{}
+
+func (h *FailingHandler) Fail(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) error {
+	return errors.New("no kittens today")
+}
+
+// Reason is exported but does not have the signature of an RPC method
+func (h *FailingHandler) Reason() string {
+	return "no kittens today"
+}
+
+// CountingHandler records the largest number of calls it served at once
+type CountingHandler struct {
+	running int32
+	max     int32
+}
+
+func (h *CountingHandler) Count(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) error {
+	n := atomic.AddInt32(&h.running, 1)
+	defer atomic.AddInt32(&h.running, -1)
+
+	for {
+		max := atomic.LoadInt32(&h.max)
+		if n <= max || atomic.CompareAndSwapInt32(&h.max, max, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+
+	return nil
+}
+
+func post(t *testing.T, body string) (*http.Response, string) {
+	s := NewServer()
+	s.Register(&HelloWorldHandler{})
+	s.Register(&FailingHandler{})
+
+	rec := httptest.NewRecorder()
+	NewHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)))
+
+	resp := rec.Result()
+	b, _ := ioutil.ReadAll(resp.Body)
+
+	return resp, string(b)
+}
+
+func TestServesCall(t *testing.T) {
+	_, body := post(t, `{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": {"name": "World"}}`)
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "id": 1, "result": {"message": "Hello World"}}`, body)
+}
+
+func TestAcceptsPositionalParams(t *testing.T) {
+	_, body := post(t, `{"jsonrpc": "2.0", "id": "a", "method": "HelloWorldHandler.HelloWorld", "params": [{"name": "World"}]}`)
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "id": "a", "result": {"message": "Hello World"}}`, body)
+}
+
+func TestServesBatch(t *testing.T) {
+	_, body := post(t, `[
+		{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": {"name": "Felix"}},
+		{"jsonrpc": "2.0", "method": "HelloWorldHandler.HelloWorld", "params": {"name": "nobody"}},
+		{"jsonrpc": "2.0", "id": 2, "method": "HelloWorldHandler.Missing"},
+		1,
+		{"jsonrpc": "2.0", "id": 3, "method": "HelloWorldHandler.HelloWorld", "params": {"name": "Garfield"}}
+	]`)
+
+	assert.JSONEq(t, `[
+		{"jsonrpc": "2.0", "id": 1, "result": {"message": "Hello Felix"}},
+		{"jsonrpc": "2.0", "id": 2, "error": {"code": -32601, "message": "Method not found", "data": "HelloWorldHandler.Missing"}},
+		{"jsonrpc": "2.0", "id": null, "error": {"code": -32600, "message": "Invalid Request"}},
+		{"jsonrpc": "2.0", "id": 3, "result": {"message": "Hello Garfield"}}
+	]`, body)
+}
+
+func TestLimitsBatchConcurrency(t *testing.T) {
+	h := &CountingHandler{}
+	s := NewServer()
+	s.Register(h)
+
+	calls := make([]string, 50)
+	for i := range calls {
+		calls[i] = fmt.Sprintf(`{"jsonrpc": "2.0", "id": %v, "method": "CountingHandler.Count", "params": {}}`, i)
+	}
+
+	rec := httptest.NewRecorder()
+	NewHandler(s).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("["+strings.Join(calls, ",")+"]")))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, atomic.LoadInt32(&h.max) <= maxBatchConcurrency)
+}
+
+func TestNotificationsGetNoResponse(t *testing.T) {
+	resp, body := post(t, `[{"jsonrpc": "2.0", "method": "HelloWorldHandler.HelloWorld", "params": {"name": "World"}}]`)
+
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "", body)
+}
+
+func TestReturnsStandardErrors(t *testing.T) {
+	cases := map[string]string{
+		`{"jsonrpc": "2.0", "id": 1, "method"`: `{"jsonrpc": "2.0", "id": null, "error": {"code": -32700, "message": "Parse error"}}`,
+		`[]`:                                   `{"jsonrpc": "2.0", "id": null, "error": {"code": -32600, "message": "Invalid Request"}}`,
+		`{"id": 1, "method": "HelloWorldHandler.HelloWorld"}`:                                       `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32600, "message": "Invalid Request", "data": "jsonrpc must be \"2.0\""}}`,
+		`{"jsonrpc": "2.0", "id": 1, "method": "Kittens.HelloWorld"}`:                               `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32601, "message": "Method not found", "data": "Kittens.HelloWorld"}}`,
+		`{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": [{}, {}]}`: `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32602, "message": "Invalid params", "data": "params must hold exactly one argument"}}`,
+		`{"jsonrpc": "2.0", "id": 1, "method": "FailingHandler.Fail"}`:                              `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32000, "message": "no kittens today"}}`,
+	}
+
+	for request, expected := range cases {
+		_, body := post(t, request)
+		assert.JSONEq(t, expected, body, request)
+	}
+}
+
+func TestDoesNotServeUnsuitableMethods(t *testing.T) {
+	_, body := post(t, `{"jsonrpc": "2.0", "id": 1, "method": "FailingHandler.Reason"}`)
+
+	assert.JSONEq(t, `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32601, "message": "Method not found", "data": "FailingHandler.Reason"}}`, body)
+}
+
+// errReader fails every read
+type errReader struct{}
+
+func (errReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestRejectsBodyWhichCanNotBeRead(t *testing.T) {
+	rec := httptest.NewRecorder()
+	NewHandler(NewServer()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/", errReader{}))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestInvalidParams(t *testing.T) {
+	_, body := post(t, `{"jsonrpc": "2.0", "id": 1, "method": "HelloWorldHandler.HelloWorld", "params": {"name": 1}}`)
+
+	assert.Contains(t, body, `"code":-32602`)
+}
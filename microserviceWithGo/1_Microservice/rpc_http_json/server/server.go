@@ -2,27 +2,15 @@ package server
 
 import (
 	"fmt"
-	"io"
 	"log"
 	"net"
 	"net/http"
-	"net/rpc"
-	"net/rpc/jsonrpc"
 
 	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_http_json/contract"
 )
 
 const port = 1234
 
-type HttpConn struct {
-	in  io.Reader
-	out io.Writer
-}
-
-func (c *HttpConn) Read(p []byte) (n int, err error)  { return c.in.Read(p) }
-func (c *HttpConn) Write(d []byte) (n int, err error) { return c.out.Write(d) }
-func (c *HttpConn) Close() error                      { return nil }
-
 type HelloWorldHandler struct{}
 
 func (h *HelloWorldHandler) HelloWorld(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) error {
@@ -32,22 +20,18 @@ func (h *HelloWorldHandler) HelloWorld(args *contract.HelloWorldRequest, reply *
 
 func StartServer() {
 	helloWorld := new(HelloWorldHandler)
-	rpc.Register(helloWorld)
+	rpcServer.Register(helloWorld)
 
 	l, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
 	if err != nil {
 		log.Fatal(fmt.Sprintf("Unable to listen on given port: %s", err))
 	}
 
-	http.Serve(l, http.HandlerFunc(httpHandler))
+	http.Serve(l, httpHandler)
 }
 
-func httpHandler(w http.ResponseWriter, r *http.Request) {
-	serverCodec := jsonrpc.NewServerCodec(&HttpConn{in: r.Body, out: w})
-	err := rpc.ServeRequest(serverCodec)
-	if err != nil {
-		log.Printf("Error while serving JSON request: %v", err)
-		http.Error(w, "Error while serving JSON request, details have been logged.", 500)
-		return
-	}
-}
+// rpcServer 핸들러를 등록하는 RPC 서버, httpHandler 가 JSON-RPC 2.0 요청을 처리
+var (
+	rpcServer   = NewServer()
+	httpHandler = NewHandler(rpcServer)
+)
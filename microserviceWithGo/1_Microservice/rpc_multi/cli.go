@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc_multi/server"
+)
+
+// 하나의 포트에서 gob, JSON-RPC, HTTP 위의 JSON-RPC 를 모두 처리
+func main() {
+	go server.StartServer()
+	time.Sleep(100 * time.Millisecond)
+
+	args := &contract.HelloWorldRequest{Name: "World"}
+
+	gob, err := rpc.Dial("tcp", "localhost:1234")
+	if err != nil {
+		log.Fatal("dialing:", err)
+	}
+	defer gob.Close()
+
+	var reply contract.HelloWorldResponse
+	if err := gob.Call(contract.HelloWorldMethod, args, &reply); err != nil {
+		log.Fatal("error:", err)
+	}
+	fmt.Println("gob:", reply.Message)
+
+	json, err := jsonrpc.Dial("tcp", "localhost:1234")
+	if err != nil {
+		log.Fatal("dialing:", err)
+	}
+	defer json.Close()
+
+	if err := json.Call(contract.HelloWorldMethod, args, &reply); err != nil {
+		log.Fatal("error:", err)
+	}
+	fmt.Println("json-rpc:", reply.Message)
+
+	r, err := http.Post("http://localhost:1234", "application/json",
+		bytes.NewBufferString(`{"id": 1, "method": "HelloWorldHandler.HelloWorld", "params": [{"name":"World"}]}`))
+	if err != nil {
+		log.Fatal("error:", err)
+	}
+	defer r.Body.Close()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	fmt.Println("http:", string(body))
+}
@@ -0,0 +1,387 @@
+// Package server serves net/rpc handlers with several codecs from one
+// listener. The first bytes of every connection decide how it is served:
+//
+//	POST, GET, CONNECT ...  HTTP, POST bodies are JSON-RPC requests and
+//	                        CONNECT is the net/rpc HTTP handshake (rpc.DialHTTP)
+//	{                       JSON-RPC over TCP (jsonrpc.Dial)
+//	anything else           gob over TCP (rpc.Dial)
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"sync"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+)
+
+const port = 1234
+
+// DefaultSniffTimeout is how long a new connection may take to send its
+// first bytes when SniffTimeout is not set
+const DefaultSniffTimeout = 5 * time.Second
+
+// ErrServerClosed is returned by Serve after Shutdown or Close
+var ErrServerClosed = errors.New("server: closed")
+
+var httpMethods = [][]byte{
+	[]byte("GET "), []byte("POST "), []byte("PUT "), []byte("HEAD "), []byte("DELETE "),
+	[]byte("OPTIONS "), []byte("PATCH "), []byte("CONNECT "), []byte("TRACE "),
+}
+
+// Server serves the handlers registered on its own rpc.Server, it does not
+// touch rpc.DefaultServer
+type Server struct {
+	// SniffTimeout is how long a connection may take to send its first
+	// bytes, defaults to DefaultSniffTimeout.
+	SniffTimeout time.Duration
+
+	rpc  *rpc.Server
+	http *http.Server
+	// httpConns passes the connections which speak HTTP to the http.Server
+	httpConns *connListener
+
+	mutex     sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closed    bool
+	wg        sync.WaitGroup
+}
+
+// New creates a Server without handlers
+func New() *Server {
+	s := &Server{
+		rpc:       rpc.NewServer(),
+		listeners: map[net.Listener]struct{}{},
+		conns:     map[net.Conn]struct{}{},
+	}
+	s.httpConns = newConnListener()
+	s.http = &http.Server{Handler: http.HandlerFunc(s.serveHTTP)}
+
+	go s.http.Serve(s.httpConns)
+
+	return s
+}
+
+// Register publishes the methods of rcvr, see rpc.Server.Register
+func (s *Server) Register(rcvr interface{}) error {
+	return s.rpc.Register(rcvr)
+}
+
+// RegisterName publishes the methods of rcvr under name
+func (s *Server) RegisterName(name string, rcvr interface{}) error {
+	return s.rpc.RegisterName(name, rcvr)
+}
+
+// ListenAndServe listens on addr and serves the connections
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(l)
+}
+
+// Serve accepts connections on l until the server is shut down, it always
+// returns a non-nil error and ErrServerClosed after Shutdown or Close
+func (s *Server) Serve(l net.Listener) error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		l.Close()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mutex.Unlock()
+
+	defer func() {
+		s.mutex.Lock()
+		delete(s.listeners, l)
+		s.mutex.Unlock()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.serveConn(conn)
+		}()
+	}
+}
+
+// Shutdown stops accepting connections and waits for the HTTP requests in
+// flight until ctx is done. The TCP connections are closed, net/rpc can not
+// drain them.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeListeners()
+	err := s.http.Shutdown(ctx)
+	s.closeConns()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+
+	return err
+}
+
+// Close closes the listeners and every connection straight away
+func (s *Server) Close() error {
+	s.closeListeners()
+	err := s.http.Close()
+	s.closeConns()
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) serveConn(conn net.Conn) {
+	timeout := s.SniffTimeout
+	if timeout <= 0 {
+		timeout = DefaultSniffTimeout
+	}
+
+	// 첫 바이트를 읽어서 프로토콜을 판별, 읽은 바이트는 bufio 에 남아 있음
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	head, err := r.Peek(1)
+	if err == nil && head[0] >= 'A' && head[0] <= 'Z' {
+		// HTTP request lines and gob messages starting with these bytes are
+		// longer than the longest method
+		head, err = r.Peek(len("OPTIONS "))
+	}
+	if err != nil {
+		conn.Close()
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	sniffed := &sniffedConn{Conn: conn, r: r}
+	if isHTTP(head) {
+		// the http.Server owns the connection from here on
+		if err := s.httpConns.push(sniffed); err != nil {
+			conn.Close()
+		}
+		return
+	}
+
+	if !s.track(conn) {
+		conn.Close()
+		return
+	}
+	defer s.untrack(conn)
+
+	if head[0] == '{' {
+		s.rpc.ServeCodec(jsonrpc.NewServerCodec(sniffed))
+		return
+	}
+
+	s.rpc.ServeConn(sniffed)
+}
+
+// serveHTTP serves a JSON-RPC request from the body of a POST, CONNECT
+// requests switch the connection to gob like rpc.HandleHTTP
+func (s *Server) serveHTTP(rw http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodConnect:
+		s.serveConnect(rw)
+	case http.MethodPost:
+		rw.Header().Set("Content-Type", "application/json")
+		codec := jsonrpc.NewServerCodec(&httpConn{in: r.Body, out: rw})
+		if err := s.rpc.ServeRequest(codec); err != nil {
+			log.Printf("Error while serving JSON request: %v", err)
+			http.Error(rw, "Error while serving JSON request, details have been logged.", http.StatusBadRequest)
+		}
+	default:
+		rw.Header().Set("Allow", "POST, CONNECT")
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveConnect takes over the connection like rpc.Server.ServeHTTP but
+// tracks it, the http.Server forgets hijacked connections so Shutdown and
+// Close would not reach it otherwise
+func (s *Server) serveConnect(rw http.ResponseWriter) {
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		http.Error(rw, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+
+	conn, buf, err := hj.Hijack()
+	if err != nil {
+		log.Printf("Error while hijacking %v: %v", rw, err)
+		return
+	}
+	io.WriteString(conn, "HTTP/1.0 200 Connected to Go RPC\n\n")
+
+	if !s.track(conn) {
+		conn.Close()
+		return
+	}
+	defer s.untrack(conn)
+
+	s.rpc.ServeConn(&sniffedConn{Conn: conn, r: buf.Reader})
+}
+
+func (s *Server) isClosed() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.closed
+}
+
+// track registers a connection for closeConns and counts it in wg until
+// untrack, it returns false once the server is closed
+func (s *Server) track(conn net.Conn) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+
+	return true
+}
+
+func (s *Server) untrack(conn net.Conn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.conns, conn)
+	s.wg.Done()
+}
+
+func (s *Server) closeListeners() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+	for l := range s.listeners {
+		l.Close()
+	}
+	s.httpConns.Close()
+}
+
+func (s *Server) closeConns() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for c := range s.conns {
+		c.Close()
+	}
+}
+
+func isHTTP(head []byte) bool {
+	for _, m := range httpMethods {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sniffedConn reads the bytes consumed while sniffing before the rest of the
+// connection
+type sniffedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *sniffedConn) Read(p []byte) (int, error) { return c.r.Read(p) }
+
+// httpConn is the connection of a single JSON-RPC request over HTTP
+type httpConn struct {
+	in  io.Reader
+	out io.Writer
+}
+
+func (c *httpConn) Read(p []byte) (n int, err error)  { return c.in.Read(p) }
+func (c *httpConn) Write(d []byte) (n int, err error) { return c.out.Write(d) }
+func (c *httpConn) Close() error                      { return nil }
+
+// connListener is a net.Listener which accepts the connections pushed to it
+type connListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newConnListener() *connListener {
+	return &connListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *connListener) push(conn net.Conn) error {
+	select {
+	case l.conns <- conn:
+		return nil
+	case <-l.done:
+		return ErrServerClosed
+	}
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, ErrServerClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return &net.TCPAddr{}
+}
+
+type HelloWorldHandler struct{}
+
+func (h *HelloWorldHandler) HelloWorld(args *contract.HelloWorldRequest, reply *contract.HelloWorldResponse) error {
+	reply.Message = "Hello " + args.Name
+	return nil
+}
+
+func StartServer() {
+	s := New()
+	s.RegisterName(contract.HelloWorldService, &HelloWorldHandler{})
+
+	log.Printf("Server starting on port %v\n", port)
+	if err := s.ListenAndServe(fmt.Sprintf(":%v", port)); err != nil && err != ErrServerClosed {
+		log.Fatal(fmt.Sprintf("Unable to listen on given port: %s", err))
+	}
+}
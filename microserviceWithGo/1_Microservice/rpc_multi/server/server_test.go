@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/1_Microservice/rpc/contract"
+)
+
+func setupServer(t *testing.T) (*Server, string, chan error) {
+	return serve(t, New())
+}
+
+func serve(t *testing.T, s *Server) (*Server, string, chan error) {
+	s.RegisterName(contract.HelloWorldService, &HelloWorldHandler{})
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(l) }()
+	t.Cleanup(func() { s.Close() })
+
+	return s, l.Addr().String(), served
+}
+
+func call(t *testing.T, c *rpc.Client) string {
+	var reply contract.HelloWorldResponse
+	err := c.Call(contract.HelloWorldMethod, &contract.HelloWorldRequest{Name: "World"}, &reply)
+	assert.Nil(t, err)
+
+	return reply.Message
+}
+
+func TestServesGob(t *testing.T) {
+	_, addr, _ := setupServer(t)
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	assert.Equal(t, "Hello World", call(t, c))
+}
+
+func TestServesJSONRPC(t *testing.T) {
+	_, addr, _ := setupServer(t)
+
+	c, err := jsonrpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	assert.Equal(t, "Hello World", call(t, c))
+}
+
+func TestServesGobOverHTTPConnect(t *testing.T) {
+	_, addr, _ := setupServer(t)
+
+	c, err := rpc.DialHTTP("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+
+	assert.Equal(t, "Hello World", call(t, c))
+}
+
+func TestServesJSONRPCOverHTTP(t *testing.T) {
+	_, addr, _ := setupServer(t)
+
+	r, err := http.Post("http://"+addr, "application/json",
+		bytes.NewBufferString(`{"id": 1, "method": "HelloWorldHandler.HelloWorld", "params": [{"name":"World"}]}`))
+	assert.Nil(t, err)
+	defer r.Body.Close()
+
+	body, _ := ioutil.ReadAll(r.Body)
+	assert.Equal(t, http.StatusOK, r.StatusCode)
+	assert.JSONEq(t, `{"id": 1, "result": {"Message": "Hello World"}, "error": null}`, string(body))
+
+	r, err = http.Get("http://" + addr)
+	assert.Nil(t, err)
+	r.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, r.StatusCode)
+}
+
+func TestClosesSilentConnections(t *testing.T) {
+	s := New()
+	s.SniffTimeout = 10 * time.Millisecond
+	_, addr, _ := serve(t, s)
+
+	conn, err := net.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.NotNil(t, err)
+	assert.False(t, isTimeout(err))
+}
+
+func TestShutdownStopsServing(t *testing.T) {
+	s, addr, served := setupServer(t)
+
+	c, err := rpc.Dial("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+	call(t, c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, s.Shutdown(ctx))
+	assert.Equal(t, ErrServerClosed, <-served)
+
+	err = c.Call(contract.HelloWorldMethod, &contract.HelloWorldRequest{}, &contract.HelloWorldResponse{})
+	assert.NotNil(t, err)
+
+	_, err = net.Dial("tcp", addr)
+	assert.NotNil(t, err)
+}
+
+func TestShutdownStopsServingHTTPConnect(t *testing.T) {
+	s, addr, served := setupServer(t)
+
+	c, err := rpc.DialHTTP("tcp", addr)
+	assert.Nil(t, err)
+	defer c.Close()
+	call(t, c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.Nil(t, s.Shutdown(ctx))
+	assert.Equal(t, ErrServerClosed, <-served)
+
+	err = c.Call(contract.HelloWorldMethod, &contract.HelloWorldRequest{}, &contract.HelloWorldResponse{})
+	assert.NotNil(t, err)
+}
+
+func isTimeout(err error) bool {
+	ne, ok := err.(net.Error)
+	return ok && ne.Timeout()
+}
@@ -1,6 +1,34 @@
 package data
 
+import "errors"
+
+var (
+	// ErrNotFound is returned when no kitten has the given id
+	ErrNotFound = errors.New("data: kitten not found")
+	// ErrExists is returned when creating a kitten with an id which is
+	// already taken
+	ErrExists = errors.New("data: kitten already exists")
+)
+
 // Store is an interface used for interacting with the backend datastore
 type Store interface {
 	Search(name string) []Kitten
+	// List returns every kitten ordered by id
+	List() []Kitten
+	// Get returns the kitten with the given id or ErrNotFound
+	Get(id string) (Kitten, error)
+	// Create stores a new kitten and returns it, an id is assigned when the
+	// kitten has none
+	Create(kitten Kitten) (Kitten, error)
+	// Update replaces the kitten with the same id or returns ErrNotFound
+	Update(kitten Kitten) (Kitten, error)
+	// Delete removes the kitten with the given id and returns it or returns
+	// ErrNotFound
+	Delete(id string) (Kitten, error)
+}
+
+// Pinger is implemented by stores which depend on a remote database, Ping
+// returns an error when the database can not be reached
+type Pinger interface {
+	Ping() error
 }
@@ -1,5 +1,11 @@
 package data
 
+import (
+	"sort"
+	"strconv"
+	"sync"
+)
+
 var data = []Kitten{
 	Kitten{
 		Id:     "1",
@@ -18,19 +24,142 @@ var data = []Kitten{
 	},
 }
 
-// MemoryStore is a simple in memory datastore that implements Store
+// MemoryStore is a simple in memory datastore that implements Store, the
+// zero value starts with the sample kittens
 type MemoryStore struct {
+	once    sync.Once
+	mutex   sync.RWMutex
+	kittens map[string]Kitten
+	lastID  int
+}
+
+func (m *MemoryStore) init() {
+	m.once.Do(func() {
+		m.kittens = map[string]Kitten{}
+		for _, k := range data {
+			m.kittens[k.Id] = k
+			if id, err := strconv.Atoi(k.Id); err == nil && id > m.lastID {
+				m.lastID = id
+			}
+		}
+	})
 }
 
 //Search returns a slice of Kitten which have a name matching the name in the parameters
 func (m *MemoryStore) Search(name string) []Kitten {
+	m.init()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
 	var kittens []Kitten
 
-	for _, k := range data {
+	for _, k := range m.kittens {
 		if k.Name == name {
 			kittens = append(kittens, k)
 		}
 	}
 
+	sort.Slice(kittens, func(i, j int) bool { return lessID(kittens[i].Id, kittens[j].Id) })
+
 	return kittens
 }
+
+// List returns every kitten ordered by id
+func (m *MemoryStore) List() []Kitten {
+	m.init()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	kittens := make([]Kitten, 0, len(m.kittens))
+	for _, k := range m.kittens {
+		kittens = append(kittens, k)
+	}
+
+	sort.Slice(kittens, func(i, j int) bool { return lessID(kittens[i].Id, kittens[j].Id) })
+
+	return kittens
+}
+
+// Get returns the kitten with the given id
+func (m *MemoryStore) Get(id string) (Kitten, error) {
+	m.init()
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	k, ok := m.kittens[id]
+	if !ok {
+		return Kitten{}, ErrNotFound
+	}
+
+	return k, nil
+}
+
+// Create stores a new kitten, kittens without an id get the next free number
+func (m *MemoryStore) Create(kitten Kitten) (Kitten, error) {
+	m.init()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if kitten.Id == "" {
+		for {
+			m.lastID++
+			kitten.Id = strconv.Itoa(m.lastID)
+			if _, ok := m.kittens[kitten.Id]; !ok {
+				break
+			}
+		}
+	}
+	if _, ok := m.kittens[kitten.Id]; ok {
+		return Kitten{}, ErrExists
+	}
+
+	m.kittens[kitten.Id] = kitten
+
+	return kitten, nil
+}
+
+// Update replaces the kitten with the same id
+func (m *MemoryStore) Update(kitten Kitten) (Kitten, error) {
+	m.init()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if _, ok := m.kittens[kitten.Id]; !ok {
+		return Kitten{}, ErrNotFound
+	}
+
+	m.kittens[kitten.Id] = kitten
+
+	return kitten, nil
+}
+
+// Delete removes the kitten with the given id and returns it
+func (m *MemoryStore) Delete(id string) (Kitten, error) {
+	m.init()
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	k, ok := m.kittens[id]
+	if !ok {
+		return Kitten{}, ErrNotFound
+	}
+
+	delete(m.kittens, id)
+
+	return k, nil
+}
+
+// lessID orders numeric ids by their value and before all other ids, which
+// are ordered as strings
+func lessID(a, b string) bool {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return x < y
+	case errA == nil || errB == nil:
+		return errA == nil
+	default:
+		return a < b
+	}
+}
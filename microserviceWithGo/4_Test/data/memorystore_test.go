@@ -19,3 +19,72 @@ func TestReturns0KittenWhenSearchTom(t *testing.T) {
 
 	assert.Equal(t, 0, len(kittens))
 }
+
+func TestCreateAssignsNextId(t *testing.T) {
+	store := MemoryStore{}
+	kitten, err := store.Create(Kitten{Name: "Tom"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "4", kitten.Id)
+	assert.Equal(t, 1, len(store.Search("Tom")))
+}
+
+func TestCreateReturnsErrExistsForTakenId(t *testing.T) {
+	store := MemoryStore{}
+	_, err := store.Create(Kitten{Id: "1", Name: "Tom"})
+
+	assert.Equal(t, ErrExists, err)
+}
+
+func TestUpdateReplacesKitten(t *testing.T) {
+	store := MemoryStore{}
+	_, err := store.Update(Kitten{Id: "3", Name: "Garfield", Weight: 40})
+	kitten, _ := store.Get("3")
+
+	assert.Nil(t, err)
+	assert.Equal(t, float32(40), kitten.Weight)
+}
+
+func TestReturnsErrNotFoundForUnknownId(t *testing.T) {
+	store := MemoryStore{}
+
+	_, err := store.Get("42")
+	assert.Equal(t, ErrNotFound, err)
+	_, err = store.Update(Kitten{Id: "42"})
+	assert.Equal(t, ErrNotFound, err)
+	_, err = store.Delete("42")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestDeleteRemovesKitten(t *testing.T) {
+	store := MemoryStore{}
+
+	kitten, err := store.Delete("3")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Garfield", kitten.Name)
+	assert.Equal(t, 0, len(store.Search("Garfield")))
+}
+
+func TestSearchOrdersIdsNumerically(t *testing.T) {
+	store := MemoryStore{}
+	for i := 0; i < 8; i++ {
+		store.Create(Kitten{Name: "Felix"})
+	}
+
+	var ids []string
+	for _, k := range store.Search("Felix") {
+		ids = append(ids, k.Id)
+	}
+
+	assert.Equal(t, []string{"1", "4", "5", "6", "7", "8", "9", "10", "11"}, ids)
+}
+
+func TestListReturnsAllKittensOrderedById(t *testing.T) {
+	store := MemoryStore{}
+	kittens := store.List()
+
+	assert.Equal(t, 3, len(kittens))
+	assert.Equal(t, "1", kittens[0].Id)
+	assert.Equal(t, "3", kittens[2].Id)
+}
@@ -13,3 +13,38 @@ func (m *MockStore) Search(name string) []Kitten {
 
 	return args.Get(0).([]Kitten)
 }
+
+// List returns the object which was passed to the mock on setup
+func (m *MockStore) List() []Kitten {
+	args := m.Mock.Called()
+
+	return args.Get(0).([]Kitten)
+}
+
+// Get returns the objects which were passed to the mock on setup
+func (m *MockStore) Get(id string) (Kitten, error) {
+	args := m.Mock.Called(id)
+
+	return args.Get(0).(Kitten), args.Error(1)
+}
+
+// Create returns the objects which were passed to the mock on setup
+func (m *MockStore) Create(kitten Kitten) (Kitten, error) {
+	args := m.Mock.Called(kitten)
+
+	return args.Get(0).(Kitten), args.Error(1)
+}
+
+// Update returns the objects which were passed to the mock on setup
+func (m *MockStore) Update(kitten Kitten) (Kitten, error) {
+	args := m.Mock.Called(kitten)
+
+	return args.Get(0).(Kitten), args.Error(1)
+}
+
+// Delete returns the kitten and error which were passed to the mock on setup
+func (m *MockStore) Delete(id string) (Kitten, error) {
+	args := m.Mock.Called(id)
+
+	return args.Get(0).(Kitten), args.Error(1)
+}
@@ -1,6 +1,9 @@
 package data
 
-import "labix.org/v2/mgo"
+import (
+	"labix.org/v2/mgo"
+	"labix.org/v2/mgo/bson"
+)
 
 // MongoStore is a MongoDB data store which implements the Store interface
 type MongoStore struct {
@@ -32,6 +35,97 @@ func (m *MongoStore) Search(name string) []Kitten {
 	return results
 }
 
+// List returns every Kitten from the MongoDB instance ordered by id
+func (m *MongoStore) List() []Kitten {
+	s := m.session.Clone()
+	defer s.Close()
+
+	var results []Kitten
+	err := s.DB("kittenserver").C("kittens").Find(nil).Sort("id").All(&results)
+	if err != nil {
+		return nil
+	}
+
+	return results
+}
+
+// Get returns the Kitten with the given id
+func (m *MongoStore) Get(id string) (Kitten, error) {
+	s := m.session.Clone()
+	defer s.Close()
+
+	var kitten Kitten
+	err := s.DB("kittenserver").C("kittens").Find(bson.M{"id": id}).One(&kitten)
+	if err == mgo.ErrNotFound {
+		return Kitten{}, ErrNotFound
+	}
+
+	return kitten, err
+}
+
+// Create inserts a new Kitten, kittens without an id get a new ObjectId
+func (m *MongoStore) Create(kitten Kitten) (Kitten, error) {
+	s := m.session.Clone()
+	defer s.Close()
+
+	if kitten.Id == "" {
+		kitten.Id = bson.NewObjectId().Hex()
+	}
+
+	c := s.DB("kittenserver").C("kittens")
+	n, err := c.Find(bson.M{"id": kitten.Id}).Count()
+	if err != nil {
+		return Kitten{}, err
+	}
+	if n > 0 {
+		return Kitten{}, ErrExists
+	}
+
+	if err := c.Insert(kitten); err != nil {
+		return Kitten{}, err
+	}
+
+	return kitten, nil
+}
+
+// Update replaces the Kitten with the same id
+func (m *MongoStore) Update(kitten Kitten) (Kitten, error) {
+	s := m.session.Clone()
+	defer s.Close()
+
+	err := s.DB("kittenserver").C("kittens").Update(bson.M{"id": kitten.Id}, kitten)
+	if err == mgo.ErrNotFound {
+		return Kitten{}, ErrNotFound
+	}
+	if err != nil {
+		return Kitten{}, err
+	}
+
+	return kitten, nil
+}
+
+// Delete removes the Kitten with the given id and returns it
+func (m *MongoStore) Delete(id string) (Kitten, error) {
+	s := m.session.Clone()
+	defer s.Close()
+
+	var kitten Kitten
+	_, err := s.DB("kittenserver").C("kittens").Find(bson.M{"id": id}).Apply(mgo.Change{Remove: true}, &kitten)
+	if err == mgo.ErrNotFound {
+		return Kitten{}, ErrNotFound
+	}
+
+	return kitten, err
+}
+
+// Ping checks that the MongoDB instance can be reached
+func (m *MongoStore) Ping() error {
+	s := m.session.Clone()
+	defer s.Close()
+
+	return s.Ping()
+}
+
 // DeleteAllKittens deletes all the kittens from the datastore
 func (m *MongoStore) DeleteAllKittens() {
 	s := m.session.Clone()
@@ -1,17 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"time"
 
-	"github.com/eapache/go-resiliency/retrier"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
 )
 
 func main() {
 	n := 0
-	r := retrier.New(retrier.ConstantBackoff(3, 1*time.Second), nil)
+	// 100ms 부터 두 배씩 대기 시간을 늘리고, 무작위 지터로 재시도가 몰리지 않게 한다.
+	p := retry.Policy{
+		MaxAttempts: 4,
+		Backoff:     retry.ExponentialBackoff{Base: 100 * time.Millisecond, Max: 1 * time.Second, Jitter: true},
+		Budget:      retry.NewBudget(0.1, 1, 10),
+	}
 
-	err := r.Run(func() error {
+	err := p.Do(context.Background(), func(ctx context.Context) error {
 		fmt.Println("Attempt: ", n)
 		n++
 		return fmt.Errorf("Failed")
@@ -0,0 +1,156 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// ErrFull is returned when all slots are in use and the queue is full
+	ErrFull = errors.New("bulkhead: too many concurrent calls")
+	// ErrTimeout is returned when a call waited in the queue for longer than
+	// MaxWait
+	ErrTimeout = errors.New("bulkhead: timed out waiting for a free slot")
+)
+
+// Config limits the calls going through a Bulkhead
+type Config struct {
+	// MaxConcurrent is the number of calls which may run at the same time,
+	// defaults to 10.
+	MaxConcurrent int
+	// MaxQueue is the number of calls which may wait for a free slot, calls
+	// are rejected straight away when it is 0.
+	MaxQueue int
+	// MaxWait is the longest a call waits in the queue, calls wait until
+	// their context is done when it is 0.
+	MaxWait time.Duration
+}
+
+// Metrics describe the usage of a Bulkhead
+type Metrics struct {
+	Name          string
+	MaxConcurrent int
+	MaxQueue      int
+	Active        int
+	Queued        int
+	// Saturation is the fraction of slots in use, from 0 to 1
+	Saturation float64
+	Accepted   uint64
+	Rejected   uint64
+	TimedOut   uint64
+}
+
+// Bulkhead isolates the calls to a dependency in their own bounded pool, so
+// that a slow dependency can only tie up its own slots instead of every
+// goroutine of the service.
+type Bulkhead struct {
+	name   string
+	config Config
+	slots  chan struct{}
+	queue  chan struct{}
+
+	accepted uint64
+	rejected uint64
+	timedOut uint64
+}
+
+// New creates a Bulkhead with the given name and config
+func New(name string, config Config) *Bulkhead {
+	if config.MaxConcurrent <= 0 {
+		config.MaxConcurrent = 10
+	}
+	if config.MaxQueue < 0 {
+		config.MaxQueue = 0
+	}
+
+	return &Bulkhead{
+		name:   name,
+		config: config,
+		slots:  make(chan struct{}, config.MaxConcurrent),
+		queue:  make(chan struct{}, config.MaxQueue),
+	}
+}
+
+// Name returns the name of the bulkhead
+func (b *Bulkhead) Name() string {
+	return b.name
+}
+
+// Execute runs fn once a slot is free, ErrFull or ErrTimeout are returned
+// without running fn when no slot could be acquired.
+func (b *Bulkhead) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	release, err := b.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	return fn(ctx)
+}
+
+// Acquire waits for a free slot, the returned function must be called to
+// release it once the call has finished.
+func (b *Bulkhead) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case b.slots <- struct{}{}:
+		return b.accept(), nil
+	default:
+	}
+
+	// no free slot, wait in the queue when there is room
+	select {
+	case b.queue <- struct{}{}:
+	default:
+		atomic.AddUint64(&b.rejected, 1)
+		return nil, ErrFull
+	}
+	defer func() { <-b.queue }()
+
+	var timeout <-chan time.Time
+	if b.config.MaxWait > 0 {
+		timer := time.NewTimer(b.config.MaxWait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case b.slots <- struct{}{}:
+		return b.accept(), nil
+	case <-timeout:
+		atomic.AddUint64(&b.timedOut, 1)
+		return nil, ErrTimeout
+	case <-ctx.Done():
+		atomic.AddUint64(&b.rejected, 1)
+		return nil, ctx.Err()
+	}
+}
+
+func (b *Bulkhead) accept() func() {
+	atomic.AddUint64(&b.accepted, 1)
+
+	released := int32(0)
+	return func() {
+		if atomic.CompareAndSwapInt32(&released, 0, 1) {
+			<-b.slots
+		}
+	}
+}
+
+// Metrics returns the current usage of the bulkhead
+func (b *Bulkhead) Metrics() Metrics {
+	active := len(b.slots)
+
+	return Metrics{
+		Name:          b.name,
+		MaxConcurrent: b.config.MaxConcurrent,
+		MaxQueue:      b.config.MaxQueue,
+		Active:        active,
+		Queued:        len(b.queue),
+		Saturation:    float64(active) / float64(b.config.MaxConcurrent),
+		Accepted:      atomic.LoadUint64(&b.accepted),
+		Rejected:      atomic.LoadUint64(&b.rejected),
+		TimedOut:      atomic.LoadUint64(&b.timedOut),
+	}
+}
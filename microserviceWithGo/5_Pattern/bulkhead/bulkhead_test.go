@@ -0,0 +1,177 @@
+package bulkhead
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// block runs a call in the bulkhead which holds its slot until released
+func block(b *Bulkhead) (release func()) {
+	started := make(chan struct{})
+	done := make(chan struct{})
+
+	go b.Execute(context.Background(), func(ctx context.Context) error {
+		close(started)
+		<-done
+		return nil
+	})
+	<-started
+
+	once := sync.Once{}
+	return func() { once.Do(func() { close(done) }) }
+}
+
+func TestLimitsConcurrentCalls(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 2})
+	r1 := block(b)
+	r2 := block(b)
+	defer r1()
+	defer r2()
+
+	err := b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+
+	assert.Equal(t, ErrFull, err)
+	assert.Equal(t, 2, b.Metrics().Active)
+	assert.Equal(t, 1.0, b.Metrics().Saturation)
+}
+
+func TestQueuedCallRunsWhenSlotIsReleased(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 1, MaxQueue: 1})
+	release := block(b)
+
+	time.AfterFunc(10*time.Millisecond, release)
+	called := false
+	err := b.Execute(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.True(t, called)
+}
+
+func TestRejectsCallsWhenQueueIsFull(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 1, MaxQueue: 1})
+	release := block(b)
+	defer release()
+
+	queued := make(chan error)
+	go func() {
+		queued <- b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	}()
+	for b.Metrics().Queued == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	err := b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	assert.Equal(t, ErrFull, err)
+
+	release()
+	assert.Nil(t, <-queued)
+}
+
+func TestTimesOutWaitingInQueue(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 1, MaxQueue: 1, MaxWait: 10 * time.Millisecond})
+	release := block(b)
+	defer release()
+
+	err := b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+
+	assert.Equal(t, ErrTimeout, err)
+	assert.Equal(t, uint64(1), b.Metrics().TimedOut)
+}
+
+func TestStopsWaitingWhenContextIsDone(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 1, MaxQueue: 1})
+	release := block(b)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := b.Execute(ctx, func(ctx context.Context) error { return nil })
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestRecordsMetrics(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 1})
+	b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	release := block(b)
+	b.Execute(context.Background(), func(ctx context.Context) error { return nil })
+	release()
+
+	m := b.Metrics()
+	assert.Equal(t, "kittens", m.Name)
+	assert.Equal(t, uint64(2), m.Accepted)
+	assert.Equal(t, uint64(1), m.Rejected)
+}
+
+func TestReleaseIsIdempotent(t *testing.T) {
+	b := New("kittens", Config{MaxConcurrent: 2})
+	release, _ := b.Acquire(context.Background())
+	other := block(b)
+	defer other()
+
+	release()
+	release()
+
+	assert.Equal(t, 1, b.Metrics().Active)
+}
+
+func TestRegistryIsolatesDependencies(t *testing.T) {
+	r := NewRegistry(Config{MaxConcurrent: 1})
+	r.Configure("search", Config{MaxConcurrent: 2})
+	release := block(r.Get("payments"))
+	defer release()
+
+	assert.Equal(t, ErrFull, r.Execute(context.Background(), "payments", func(ctx context.Context) error { return nil }))
+	assert.Nil(t, r.Execute(context.Background(), "search", func(ctx context.Context) error { return nil }))
+
+	metrics := r.Metrics()
+	assert.Equal(t, 2, len(metrics))
+	assert.Equal(t, "payments", metrics[0].Name)
+	assert.Equal(t, 2, metrics[1].MaxConcurrent)
+}
+
+func TestTransportLimitsRequestsPerHost(t *testing.T) {
+	unblock := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {}))
+	defer fast.Close()
+
+	registry := NewRegistry(Config{MaxConcurrent: 1})
+	client := &http.Client{Transport: &Transport{Registry: registry}}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		resp, err := client.Get(slow.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+	for len(registry.Metrics()) == 0 || registry.Metrics()[0].Active == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := client.Get(slow.URL)
+	assert.True(t, errors.Is(err, ErrFull))
+
+	resp, err := client.Get(fast.URL)
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	close(unblock)
+	wg.Wait()
+}
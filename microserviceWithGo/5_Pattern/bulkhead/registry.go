@@ -0,0 +1,122 @@
+package bulkhead
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Registry holds a Bulkhead for every named dependency. Dependencies which
+// have not been configured get a bulkhead with the default config.
+type Registry struct {
+	defaults Config
+
+	mutex     sync.RWMutex
+	configs   map[string]Config
+	bulkheads map[string]*Bulkhead
+}
+
+// NewRegistry creates a Registry which uses defaults for dependencies
+// without their own config
+func NewRegistry(defaults Config) *Registry {
+	return &Registry{
+		defaults:  defaults,
+		configs:   map[string]Config{},
+		bulkheads: map[string]*Bulkhead{},
+	}
+}
+
+// Configure sets the config of a dependency, it must be called before the
+// bulkhead of the dependency is first used.
+func (r *Registry) Configure(name string, config Config) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.configs[name] = config
+	delete(r.bulkheads, name)
+}
+
+// Get returns the bulkhead of the named dependency, creating it when needed
+func (r *Registry) Get(name string) *Bulkhead {
+	r.mutex.RLock()
+	b, ok := r.bulkheads[name]
+	r.mutex.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if b, ok := r.bulkheads[name]; ok {
+		return b
+	}
+
+	config, ok := r.configs[name]
+	if !ok {
+		config = r.defaults
+	}
+
+	b = New(name, config)
+	r.bulkheads[name] = b
+
+	return b
+}
+
+// Execute runs fn in the bulkhead of the named dependency
+func (r *Registry) Execute(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	return r.Get(name).Execute(ctx, fn)
+}
+
+// Metrics returns the metrics of every bulkhead sorted by name
+func (r *Registry) Metrics() []Metrics {
+	r.mutex.RLock()
+	metrics := make([]Metrics, 0, len(r.bulkheads))
+	for _, b := range r.bulkheads {
+		metrics = append(metrics, b.Metrics())
+	}
+	r.mutex.RUnlock()
+
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+
+	return metrics
+}
+
+// Transport is an http.RoundTripper which sends every request through the
+// bulkhead of its host
+type Transport struct {
+	Registry *Registry
+	// Base sends the requests, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Key returns the name of the dependency for a request, defaults to the
+	// host of the request URL.
+	Key func(r *http.Request) string
+}
+
+// RoundTrip implements http.RoundTripper. The slot is held until the
+// response headers have been received.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.URL.Host
+	if t.Key != nil {
+		key = t.Key(req)
+	}
+
+	release, err := t.Registry.Get(key).Acquire(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%v: %w", key, err)
+	}
+	defer release()
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,139 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/resilience"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+var noWait = retry.Policy{MaxAttempts: 3, Backoff: retry.ConstantBackoff(0)}
+
+func TestInjectsFaults(t *testing.T) {
+	h := NewHarness(Sequence(
+		Step{Fault: Error, Status: http.StatusBadGateway},
+		Step{Fault: Reset},
+		Step{Fault: Partial},
+		Step{},
+	), nil)
+	defer h.Close()
+
+	result := h.Drive(&http.Client{Transport: h.Transport()}, 4)
+
+	assert.Equal(t, http.StatusBadGateway, result.Outcomes[0].Status)
+	assert.NotNil(t, result.Outcomes[1].Err)
+	assert.Equal(t, http.StatusOK, result.Outcomes[2].Status)
+	assert.NotNil(t, result.Outcomes[2].Err)
+	assert.Nil(t, result.Outcomes[3].Err)
+	assert.Equal(t, "ok", result.Outcomes[3].Body)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, Counts{Requests: 4, Errors: 1, Resets: 1, Partials: 1}, h.Injector.Counts())
+}
+
+func TestDelaysRequests(t *testing.T) {
+	h := NewHarness(Sequence(Step{Delay: 20 * time.Millisecond}), nil)
+	defer h.Close()
+
+	o := h.Send(&http.Client{Transport: h.Transport()})
+
+	assert.Nil(t, o.Err)
+	assert.True(t, o.Duration >= 20*time.Millisecond)
+}
+
+func TestRandomPlanIsRepeatable(t *testing.T) {
+	config := Config{Latency: Exponential(time.Millisecond), ErrorRate: 0.2, ResetRate: 0.1, PartialRate: 0.1, Seed: 42}
+	a, b := Random(config), Random(config)
+
+	faults := map[Fault]int{}
+	for i := 0; i < 1000; i++ {
+		step := a.Next(nil)
+		assert.Equal(t, step, b.Next(nil))
+		faults[step.Fault]++
+	}
+
+	assert.InDelta(t, 200, faults[Error], 50)
+	assert.InDelta(t, 100, faults[Reset], 40)
+	assert.InDelta(t, 100, faults[Partial], 40)
+}
+
+func TestDistributions(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	assert.Equal(t, time.Second, Fixed(time.Second)(rnd))
+	for i := 0; i < 100; i++ {
+		d := Uniform(time.Millisecond, 2*time.Millisecond)(rnd)
+		assert.True(t, d >= time.Millisecond && d < 2*time.Millisecond)
+		assert.True(t, Normal(0, time.Second)(rnd) >= 0)
+	}
+	assert.Equal(t, time.Second, Bimodal(Fixed(0), Fixed(time.Second), 1)(rnd))
+}
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	h := NewHarness(Repeat(Step{Fault: Error}), nil)
+	defer h.Close()
+
+	group := circuit.NewGroup(circuit.Settings{
+		Trip:        circuit.ConsecutiveFailures(3),
+		OpenTimeout: 50 * time.Millisecond,
+	})
+	client := &http.Client{Transport: &circuit.Transport{Group: group, Base: h.Transport()}}
+
+	result := h.Drive(client, 5)
+	assert.Equal(t, 5, result.Failed)
+	assert.True(t, errors.Is(result.Outcomes[4].Err, circuit.ErrOpen))
+	// the open breaker keeps the last requests away from the service
+	assert.Equal(t, uint64(3), h.Injector.Counts().Requests)
+
+	h.Injector.SetPlan(nil)
+	time.Sleep(60 * time.Millisecond)
+
+	result = h.Drive(client, 2)
+	assert.Equal(t, 2, result.Succeeded)
+}
+
+func TestRetryRecoversFromTransientFaults(t *testing.T) {
+	h := NewHarness(Sequence(
+		Step{Fault: Error, Status: http.StatusServiceUnavailable},
+		Step{Fault: Reset},
+	), nil)
+	defer h.Close()
+
+	client := &http.Client{Transport: &retry.Transport{Policy: noWait, Base: h.Transport()}}
+	o := h.Send(client)
+
+	assert.Nil(t, o.Err)
+	assert.Equal(t, "ok", o.Body)
+	assert.Equal(t, uint64(3), h.Injector.Counts().Requests)
+}
+
+func TestPipelineRetriesPartialResponses(t *testing.T) {
+	h := NewHarness(Sequence(Step{Fault: Partial}), nil)
+	defer h.Close()
+
+	// the pipeline reads the body within the attempt, so a truncated body is
+	// retried like any other failure
+	client := &http.Client{Transport: resilience.Pipeline(resilience.Retry(noWait)).RoundTripper(h.Transport())}
+	o := h.Send(client)
+
+	assert.Nil(t, o.Err)
+	assert.Equal(t, "ok", o.Body)
+	assert.Equal(t, uint64(2), h.Injector.Counts().Requests)
+}
+
+func TestTimeoutCutsSlowResponses(t *testing.T) {
+	h := NewHarness(Sequence(Step{Delay: time.Second}), nil)
+	defer h.Close()
+
+	client := &http.Client{Transport: resilience.Pipeline(resilience.Timeout(20 * time.Millisecond)).RoundTripper(h.Transport())}
+	o := h.Send(client)
+
+	assert.True(t, errors.Is(o.Err, context.DeadlineExceeded))
+	assert.True(t, o.Duration < time.Second)
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/chaos"
+)
+
+func main() {
+	// 대부분 10ms 안에 응답하지만 5% 는 1초 가까이 걸림
+	// 요청의 10% 는 500 에러, 5% 는 연결 리셋, 5% 는 응답 본문이 잘림
+	plan := chaos.Random(chaos.Config{
+		Latency: chaos.Bimodal(
+			chaos.Uniform(0, 10*time.Millisecond),
+			chaos.Normal(time.Second, 100*time.Millisecond),
+			0.05,
+		),
+		ErrorRate:   0.1,
+		ResetRate:   0.05,
+		PartialRate: 0.05,
+		Seed:        time.Now().UnixNano(),
+	})
+
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(rw, "Hello World")
+	})
+
+	http.ListenAndServe(":8080", chaos.NewInjector(plan, handler))
+}
@@ -0,0 +1,108 @@
+package chaos
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+// Outcome is the result of one request sent by a Harness
+type Outcome struct {
+	Status   int
+	Body     string
+	Err      error
+	Duration time.Duration
+}
+
+// Result sums up the outcomes of a Harness run
+type Result struct {
+	Outcomes  []Outcome
+	Succeeded int
+	Failed    int
+}
+
+// Harness runs an Injector in a local server so that the resilience patterns
+// can be driven against a downstream service with known faults
+type Harness struct {
+	Injector *Injector
+	Server   *httptest.Server
+}
+
+// NewHarness starts a server which serves handler through an Injector
+// following plan. When handler is nil every request is answered with
+// "ok".
+func NewHarness(plan Plan, handler http.Handler) *Harness {
+	if handler == nil {
+		handler = http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(rw, "ok")
+		})
+	}
+
+	injector := NewInjector(plan, handler)
+
+	return &Harness{
+		Injector: injector,
+		Server:   httptest.NewServer(injector),
+	}
+}
+
+// URL returns the address of the server
+func (h *Harness) URL() string {
+	return h.Server.URL
+}
+
+// Transport returns the transport the patterns under test should use to
+// reach the server. Keep-alives are disabled as http.Transport silently
+// resends requests which fail on a reused connection, which would consume
+// the steps of the plan out of order.
+func (h *Harness) Transport() http.RoundTripper {
+	return &http.Transport{DisableKeepAlives: true}
+}
+
+// Close stops the server
+func (h *Harness) Close() {
+	h.Server.Close()
+}
+
+// Drive sends n GET requests one after another with the given client,
+// which is where the pattern under test is plugged in. Requests which fail
+// or return a status of 500 and above count as failed.
+func (h *Harness) Drive(client *http.Client, n int) Result {
+	result := Result{}
+
+	for i := 0; i < n; i++ {
+		o := h.Send(client)
+		result.Outcomes = append(result.Outcomes, o)
+
+		if o.Err != nil || o.Status >= http.StatusInternalServerError {
+			result.Failed++
+		} else {
+			result.Succeeded++
+		}
+	}
+
+	return result
+}
+
+// Send sends one GET request with the given client and reads the full
+// response
+func (h *Harness) Send(client *http.Client) Outcome {
+	start := time.Now()
+
+	resp, err := client.Get(h.URL())
+	if err != nil {
+		return Outcome{Err: err, Duration: time.Since(start)}
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+
+	return Outcome{
+		Status:   resp.StatusCode,
+		Body:     string(body),
+		Err:      err,
+		Duration: time.Since(start),
+	}
+}
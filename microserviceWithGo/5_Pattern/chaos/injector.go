@@ -0,0 +1,158 @@
+package chaos
+
+import (
+	"bytes"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Counts are the number of requests the Injector has handled, by fault
+type Counts struct {
+	Requests uint64
+	Errors   uint64
+	Resets   uint64
+	Partials uint64
+}
+
+// Injector is middleware which injects latency and faults into the requests
+// of the next handler, following a Plan. It stands in for a flaky downstream
+// service when testing how clients cope with one.
+type Injector struct {
+	handler http.Handler
+
+	mutex sync.RWMutex
+	plan  Plan
+
+	requests uint64
+	errors   uint64
+	resets   uint64
+	partials uint64
+}
+
+// NewInjector creates a new instance of the Injector for the given plan, a
+// nil plan injects no faults
+func NewInjector(plan Plan, next http.Handler) *Injector {
+	return &Injector{handler: next, plan: plan}
+}
+
+// SetPlan replaces the plan, e.g. to let a service recover
+func (i *Injector) SetPlan(plan Plan) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.plan = plan
+}
+
+// Counts returns the number of requests handled so far
+func (i *Injector) Counts() Counts {
+	return Counts{
+		Requests: atomic.LoadUint64(&i.requests),
+		Errors:   atomic.LoadUint64(&i.errors),
+		Resets:   atomic.LoadUint64(&i.resets),
+		Partials: atomic.LoadUint64(&i.partials),
+	}
+}
+
+func (i *Injector) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	atomic.AddUint64(&i.requests, 1)
+
+	i.mutex.RLock()
+	plan := i.plan
+	i.mutex.RUnlock()
+
+	step := Step{}
+	if plan != nil {
+		step = plan.Next(r)
+	}
+
+	if step.Delay > 0 {
+		timer := time.NewTimer(step.Delay)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			// the client has given up
+			timer.Stop()
+			return
+		}
+	}
+
+	switch step.Fault {
+	case Error:
+		atomic.AddUint64(&i.errors, 1)
+		status := step.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		http.Error(rw, http.StatusText(status), status)
+	case Reset:
+		atomic.AddUint64(&i.resets, 1)
+		reset(rw)
+	case Partial:
+		atomic.AddUint64(&i.partials, 1)
+		partial(rw, r, i.handler)
+	default:
+		i.handler.ServeHTTP(rw, r)
+	}
+}
+
+// reset closes the connection with a TCP reset instead of a FIN
+func reset(rw http.ResponseWriter) {
+	hj, ok := rw.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		tcp.SetLinger(0)
+	}
+	conn.Close()
+}
+
+// partial sends the full headers of the response, including the length of
+// the complete body, but only half of the body before closing the
+// connection
+func partial(rw http.ResponseWriter, r *http.Request, next http.Handler) {
+	rec := &recorder{header: make(http.Header), status: http.StatusOK}
+	next.ServeHTTP(rec, r)
+
+	for k, v := range rec.header {
+		rw.Header()[k] = v
+	}
+	body := rec.body.Bytes()
+	rw.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	rw.WriteHeader(rec.status)
+	rw.Write(body[:len(body)/2])
+	if f, ok := rw.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	// aborting the handler closes the connection without completing the
+	// response
+	panic(http.ErrAbortHandler)
+}
+
+type recorder struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+func (r *recorder) Header() http.Header {
+	return r.header
+}
+
+func (r *recorder) Write(p []byte) (int, error) {
+	return r.body.Write(p)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	r.status = status
+}
@@ -0,0 +1,189 @@
+package chaos
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Fault is the failure injected into a request
+type Fault int
+
+const (
+	// None passes the request to the next handler
+	None Fault = iota
+	// Error responds with an error status without calling the next handler
+	Error
+	// Reset closes the connection with a TCP reset before responding
+	Reset
+	// Partial sends the headers and half of the response body of the next
+	// handler, then closes the connection
+	Partial
+)
+
+func (f Fault) String() string {
+	switch f {
+	case None:
+		return "none"
+	case Error:
+		return "error"
+	case Reset:
+		return "reset"
+	case Partial:
+		return "partial"
+	default:
+		return "unknown"
+	}
+}
+
+// Step is what the Injector does with one request, the delay is applied
+// before the fault.
+type Step struct {
+	Delay time.Duration
+	Fault Fault
+	// Status is sent for Error faults, defaults to 500.
+	Status int
+}
+
+// Plan decides the step for every request
+type Plan interface {
+	Next(r *http.Request) Step
+}
+
+// PlanFunc allows the use of an ordinary function as a Plan
+type PlanFunc func(r *http.Request) Step
+
+// Next calls f(r)
+func (f PlanFunc) Next(r *http.Request) Step {
+	return f(r)
+}
+
+// Sequence is a Plan which returns the given steps in order, requests after
+// the last step are passed through without a fault. Sequences make tests
+// deterministic as the outcome of every request is known up front.
+func Sequence(steps ...Step) Plan {
+	mutex := sync.Mutex{}
+	next := 0
+
+	return PlanFunc(func(r *http.Request) Step {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if next >= len(steps) {
+			return Step{}
+		}
+		next++
+
+		return steps[next-1]
+	})
+}
+
+// Repeat is a Plan which returns the given steps in order forever
+func Repeat(steps ...Step) Plan {
+	mutex := sync.Mutex{}
+	next := 0
+
+	return PlanFunc(func(r *http.Request) Step {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if len(steps) == 0 {
+			return Step{}
+		}
+		step := steps[next%len(steps)]
+		next++
+
+		return step
+	})
+}
+
+// Distribution samples a latency
+type Distribution func(rnd *rand.Rand) time.Duration
+
+// Fixed always returns d
+func Fixed(d time.Duration) Distribution {
+	return func(rnd *rand.Rand) time.Duration { return d }
+}
+
+// Uniform returns latencies between min and max
+func Uniform(min, max time.Duration) Distribution {
+	return func(rnd *rand.Rand) time.Duration {
+		if max <= min {
+			return min
+		}
+		return min + time.Duration(rnd.Int63n(int64(max-min)))
+	}
+}
+
+// Normal returns normally distributed latencies, negative samples are
+// returned as 0
+func Normal(mean, stddev time.Duration) Distribution {
+	return func(rnd *rand.Rand) time.Duration {
+		return time.Duration(math.Max(0, rnd.NormFloat64()*float64(stddev)+float64(mean)))
+	}
+}
+
+// Exponential returns exponentially distributed latencies with the given
+// mean, which gives a long tail
+func Exponential(mean time.Duration) Distribution {
+	return func(rnd *rand.Rand) time.Duration {
+		return time.Duration(rnd.ExpFloat64() * float64(mean))
+	}
+}
+
+// Bimodal samples slow with probability p and fast otherwise, e.g. a
+// service which usually answers from a cache
+func Bimodal(fast, slow Distribution, p float64) Distribution {
+	return func(rnd *rand.Rand) time.Duration {
+		if rnd.Float64() < p {
+			return slow(rnd)
+		}
+		return fast(rnd)
+	}
+}
+
+// Config describes the faults of a Random plan, the rates are fractions of
+// all requests and must not add up to more than 1.
+type Config struct {
+	// Latency is added to every request, no latency is added when nil.
+	Latency     Distribution
+	ErrorRate   float64
+	ResetRate   float64
+	PartialRate float64
+	// ErrorStatus is sent for Error faults, defaults to 500.
+	ErrorStatus int
+	// Seed makes the faults repeatable for requests arriving in the same
+	// order.
+	Seed int64
+}
+
+// Random is a Plan which injects faults at random with the rates of the
+// given config
+func Random(config Config) Plan {
+	mutex := sync.Mutex{}
+	rnd := rand.New(rand.NewSource(config.Seed))
+
+	return PlanFunc(func(r *http.Request) Step {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		step := Step{Status: config.ErrorStatus}
+		if config.Latency != nil {
+			step.Delay = config.Latency(rnd)
+		}
+
+		p := rnd.Float64()
+		switch {
+		case p < config.ErrorRate:
+			step.Fault = Error
+		case p < config.ErrorRate+config.ResetRate:
+			step.Fault = Reset
+		case p < config.ErrorRate+config.ResetRate+config.PartialRate:
+			step.Fault = Partial
+		}
+
+		return step
+	})
+}
@@ -0,0 +1,320 @@
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by a Breaker which is open, or half open with the
+// maximum number of trial requests in flight, instead of running the call.
+var ErrOpen = errors.New("circuit: breaker is open")
+
+// errPanicked is recorded for calls which panicked, it always counts as a
+// failure
+var errPanicked = errors.New("circuit: call panicked")
+
+// State of a Breaker
+type State int
+
+const (
+	// Closed lets all calls through and counts their failures
+	Closed State = iota
+	// Open rejects all calls with ErrOpen
+	Open
+	// HalfOpen lets a limited number of trial calls through to decide
+	// whether the breaker should close again
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// TripCondition decides from the counts of the rolling window whether a
+// closed Breaker should open
+type TripCondition func(c Counts) bool
+
+// ConsecutiveFailures trips the breaker after n failures in a row
+func ConsecutiveFailures(n int) TripCondition {
+	return func(c Counts) bool {
+		return c.ConsecutiveFailures >= n
+	}
+}
+
+// FailureCount trips the breaker after n failures in the rolling window
+func FailureCount(n int) TripCondition {
+	return func(c Counts) bool {
+		return c.Failures >= n
+	}
+}
+
+// FailureRate trips the breaker when more than rate (0 to 1) of the calls in
+// the rolling window have failed, as long as there were at least
+// minRequests calls.
+func FailureRate(rate float64, minRequests int) TripCondition {
+	return func(c Counts) bool {
+		return c.Requests >= minRequests && c.Requests > 0 &&
+			float64(c.Failures)/float64(c.Requests) > rate
+	}
+}
+
+// Settings configure a Breaker, zero values are replaced with defaults.
+type Settings struct {
+	// Trip decides when the breaker opens, defaults to
+	// ConsecutiveFailures(5).
+	Trip TripCondition
+	// Window is the length of the rolling window, defaults to 10 seconds.
+	Window time.Duration
+	// Buckets is the number of buckets the window is split into, defaults
+	// to 10.
+	Buckets int
+	// OpenTimeout is the time the breaker stays open before it lets trial
+	// calls through, defaults to 5 seconds.
+	OpenTimeout time.Duration
+	// HalfOpenSuccesses is the number of successful trial calls needed to
+	// close the breaker, at most this many trial calls run concurrently.
+	// Defaults to 1.
+	HalfOpenSuccesses int
+	// IsFailure decides whether the error returned by a call counts as a
+	// failure, defaults to any non nil error.
+	IsFailure func(err error) bool
+	// OnStateChange is called whenever the breaker changes state, it must
+	// not call back into the breaker.
+	OnStateChange func(name string, from, to State)
+}
+
+func (s Settings) withDefaults() Settings {
+	if s.Trip == nil {
+		s.Trip = ConsecutiveFailures(5)
+	}
+	if s.Window <= 0 {
+		s.Window = 10 * time.Second
+	}
+	if s.Buckets <= 0 {
+		s.Buckets = 10
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = 5 * time.Second
+	}
+	if s.HalfOpenSuccesses <= 0 {
+		s.HalfOpenSuccesses = 1
+	}
+	if s.IsFailure == nil {
+		s.IsFailure = func(err error) bool { return err != nil }
+	}
+
+	return s
+}
+
+// Metrics are the totals recorded by a Breaker since it was created
+type Metrics struct {
+	State        State
+	Requests     uint64
+	Successes    uint64
+	Failures     uint64
+	Rejections   uint64
+	StateChanges uint64
+	// Window holds the counts of the current rolling window
+	Window Counts
+}
+
+// Breaker is a circuit breaker, it stops calling a failing dependency for a
+// while so that the dependency gets the chance to recover and callers fail
+// fast instead of waiting for timeouts.
+type Breaker struct {
+	name     string
+	settings Settings
+
+	mutex               sync.Mutex
+	state               State
+	generation          uint64
+	window              *window
+	consecutiveFailures int
+	openedAt            time.Time
+	halfOpenInFlight    int
+	halfOpenSuccesses   int
+	metrics             Metrics
+}
+
+// New creates a closed Breaker, the name is passed to OnStateChange
+func New(name string, settings Settings) *Breaker {
+	settings = settings.withDefaults()
+
+	return &Breaker{
+		name:     name,
+		settings: settings,
+		window:   newWindow(settings.Window, settings.Buckets, time.Now()),
+	}
+}
+
+// Name returns the name of the breaker
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// Run calls fn when the breaker allows it and records the outcome, ErrOpen
+// is returned without calling fn when the breaker is open.
+func (b *Breaker) Run(fn func() error) error {
+	done, err := b.Allow()
+	if err != nil {
+		return err
+	}
+
+	// a panic counts as a failure, otherwise a half-open slot would never be
+	// released
+	defer func() {
+		if r := recover(); r != nil {
+			done(errPanicked)
+			panic(r)
+		}
+	}()
+
+	err = fn()
+	done(err)
+
+	return err
+}
+
+// Allow checks whether a call may be made, when it may the returned done
+// function must be called with the outcome of the call.
+func (b *Breaker) Allow() (done func(err error), err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.updateState(now)
+
+	switch b.state {
+	case Open:
+		b.metrics.Rejections++
+		return nil, ErrOpen
+	case HalfOpen:
+		if b.halfOpenInFlight >= b.settings.HalfOpenSuccesses {
+			b.metrics.Rejections++
+			return nil, ErrOpen
+		}
+		b.halfOpenInFlight++
+	}
+
+	b.metrics.Requests++
+	generation := b.generation
+	once := sync.Once{}
+
+	return func(err error) {
+		once.Do(func() { b.record(generation, err) })
+	}, nil
+}
+
+// State returns the current state of the breaker
+func (b *Breaker) State() State {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.updateState(time.Now())
+
+	return b.state
+}
+
+// Metrics returns the metrics recorded by the breaker
+func (b *Breaker) Metrics() Metrics {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	now := time.Now()
+	b.updateState(now)
+
+	m := b.metrics
+	m.State = b.state
+	m.Window = b.window.counts(now)
+	m.Window.ConsecutiveFailures = b.consecutiveFailures
+
+	return m
+}
+
+func (b *Breaker) record(generation uint64, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	failed := err == errPanicked || b.settings.IsFailure(err)
+	if failed {
+		b.metrics.Failures++
+	} else {
+		b.metrics.Successes++
+	}
+
+	// the state changed while the call was running so its outcome does not
+	// say anything about the current state
+	if generation != b.generation {
+		return
+	}
+
+	now := time.Now()
+
+	switch b.state {
+	case Closed:
+		b.window.record(now, !failed)
+		if failed {
+			b.consecutiveFailures++
+		} else {
+			b.consecutiveFailures = 0
+		}
+
+		counts := b.window.counts(now)
+		counts.ConsecutiveFailures = b.consecutiveFailures
+		if failed && b.settings.Trip(counts) {
+			b.setState(Open, now)
+		}
+	case HalfOpen:
+		b.halfOpenInFlight--
+		if failed {
+			b.setState(Open, now)
+			return
+		}
+
+		b.halfOpenSuccesses++
+		if b.halfOpenSuccesses >= b.settings.HalfOpenSuccesses {
+			b.setState(Closed, now)
+		}
+	}
+}
+
+func (b *Breaker) updateState(now time.Time) {
+	if b.state == Open && !now.Before(b.openedAt.Add(b.settings.OpenTimeout)) {
+		b.setState(HalfOpen, now)
+	}
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+
+	from := b.state
+	b.state = state
+	b.generation++
+	b.metrics.StateChanges++
+	b.halfOpenInFlight = 0
+	b.halfOpenSuccesses = 0
+
+	switch state {
+	case Closed:
+		b.window.reset(now)
+		b.consecutiveFailures = 0
+	case Open:
+		b.openedAt = now
+	}
+
+	if b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.name, from, state)
+	}
+}
@@ -0,0 +1,233 @@
+package circuit
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailed = errors.New("failed")
+
+func succeed() error { return nil }
+func fail() error    { return errFailed }
+
+func TestOpensAfterConsecutiveFailures(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(3)})
+
+	b.Run(fail)
+	b.Run(fail)
+	b.Run(succeed)
+	b.Run(fail)
+	b.Run(fail)
+	assert.Equal(t, Closed, b.State())
+
+	b.Run(fail)
+	assert.Equal(t, Open, b.State())
+}
+
+func TestRejectsCallsWhileOpen(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: time.Minute})
+	b.Run(fail)
+
+	called := false
+	err := b.Run(func() error {
+		called = true
+		return nil
+	})
+
+	assert.Equal(t, ErrOpen, err)
+	assert.False(t, called)
+}
+
+func TestOpensWhenFailureCountInWindowIsReached(t *testing.T) {
+	b := New("test", Settings{Trip: FailureCount(3)})
+
+	b.Run(fail)
+	b.Run(succeed)
+	b.Run(fail)
+	b.Run(succeed)
+	assert.Equal(t, Closed, b.State())
+
+	b.Run(fail)
+	assert.Equal(t, Open, b.State())
+}
+
+func TestOpensWhenFailureRateIsExceeded(t *testing.T) {
+	b := New("test", Settings{Trip: FailureRate(0.5, 4)})
+
+	b.Run(fail)
+	b.Run(fail)
+	b.Run(fail)
+	assert.Equal(t, Closed, b.State(), "should not open before the minimum number of requests")
+
+	b.Run(succeed)
+	b.Run(fail)
+	assert.Equal(t, Open, b.State())
+}
+
+func TestForgetsFailuresOutsideTheWindow(t *testing.T) {
+	b := New("test", Settings{Trip: FailureCount(2), Window: 40 * time.Millisecond, Buckets: 4})
+
+	b.Run(fail)
+	time.Sleep(60 * time.Millisecond)
+	b.Run(fail)
+
+	assert.Equal(t, Closed, b.State())
+	assert.Equal(t, 1, b.Metrics().Window.Failures)
+}
+
+func TestHalfOpensAfterTimeoutAndClosesOnSuccess(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: 20 * time.Millisecond})
+	b.Run(fail)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, HalfOpen, b.State())
+
+	assert.Nil(t, b.Run(succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestReopensWhenTrialCallFails(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: 20 * time.Millisecond})
+	b.Run(fail)
+	time.Sleep(30 * time.Millisecond)
+
+	b.Run(fail)
+
+	assert.Equal(t, Open, b.State())
+}
+
+func TestPanicCountsAsFailedTrialCall(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: 20 * time.Millisecond})
+	b.Run(fail)
+	time.Sleep(30 * time.Millisecond)
+
+	assert.Panics(t, func() {
+		b.Run(func() error { panic("boom") })
+	})
+
+	// the trial slot is released and the breaker opens again
+	assert.Equal(t, Open, b.State())
+	time.Sleep(30 * time.Millisecond)
+	assert.Nil(t, b.Run(succeed))
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestLimitsTrialCallsWhileHalfOpen(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: 10 * time.Millisecond, HalfOpenSuccesses: 2})
+	b.Run(fail)
+	time.Sleep(20 * time.Millisecond)
+
+	done1, err1 := b.Allow()
+	done2, err2 := b.Allow()
+	_, err3 := b.Allow()
+
+	assert.Nil(t, err1)
+	assert.Nil(t, err2)
+	assert.Equal(t, ErrOpen, err3)
+
+	done1(nil)
+	assert.Equal(t, HalfOpen, b.State())
+	done2(nil)
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestIgnoresOutcomeOfCallsFromPreviousState(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(1), OpenTimeout: 10 * time.Millisecond})
+
+	done, _ := b.Allow()
+	b.Run(fail)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, HalfOpen, b.State())
+
+	done(errFailed)
+
+	assert.Equal(t, HalfOpen, b.State())
+}
+
+func TestCallsOnStateChange(t *testing.T) {
+	var changes []string
+	b := New("payments", Settings{
+		Trip:        ConsecutiveFailures(1),
+		OpenTimeout: 10 * time.Millisecond,
+		OnStateChange: func(name string, from, to State) {
+			changes = append(changes, name+": "+from.String()+" -> "+to.String())
+		},
+	})
+
+	b.Run(fail)
+	time.Sleep(20 * time.Millisecond)
+	b.Run(succeed)
+
+	assert.Equal(t, []string{
+		"payments: closed -> open",
+		"payments: open -> half-open",
+		"payments: half-open -> closed",
+	}, changes)
+}
+
+func TestRecordsMetrics(t *testing.T) {
+	b := New("test", Settings{Trip: ConsecutiveFailures(2), OpenTimeout: time.Minute})
+
+	b.Run(succeed)
+	b.Run(fail)
+	b.Run(fail)
+	b.Run(succeed)
+
+	m := b.Metrics()
+	assert.Equal(t, Open, m.State)
+	assert.Equal(t, uint64(3), m.Requests)
+	assert.Equal(t, uint64(1), m.Successes)
+	assert.Equal(t, uint64(2), m.Failures)
+	assert.Equal(t, uint64(1), m.Rejections)
+	assert.Equal(t, uint64(1), m.StateChanges)
+}
+
+func TestUsesIsFailureToClassifyErrors(t *testing.T) {
+	errNotFound := errors.New("not found")
+	b := New("test", Settings{
+		Trip:      ConsecutiveFailures(1),
+		IsFailure: func(err error) bool { return err != nil && err != errNotFound },
+	})
+
+	b.Run(func() error { return errNotFound })
+
+	assert.Equal(t, Closed, b.State())
+}
+
+func TestGroupKeepsBreakersIndependent(t *testing.T) {
+	g := NewGroup(Settings{Trip: ConsecutiveFailures(1), OpenTimeout: time.Minute})
+
+	g.Run("a", fail)
+
+	assert.Equal(t, ErrOpen, g.Run("a", succeed))
+	assert.Nil(t, g.Run("b", succeed))
+	assert.Equal(t, Open, g.Metrics()["a"].State)
+	assert.Equal(t, Closed, g.Metrics()["b"].State)
+}
+
+func TestBreakerIsSafeForConcurrentUse(t *testing.T) {
+	g := NewGroup(Settings{Trip: FailureRate(0.5, 10)})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g.Run("a", func() error {
+				if i%3 == 0 {
+					return errFailed
+				}
+				return nil
+			})
+			g.Metrics()
+		}(i)
+	}
+	wg.Wait()
+
+	m := g.Metrics()["a"]
+	assert.Equal(t, uint64(50), m.Requests+m.Rejections)
+}
@@ -1,17 +1,25 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
-	"github.com/eapache/go-resiliency/breaker"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
 )
 
 func main() {
 	// 요청에 세 번 실패하면 차단기 열린 상태
 	// 반 개방 상태에서 1회 성공하면 닫힌 상태로 변경
 	// 열린 상태에서 5초 후에 반 개방 상태로 변경
-	b := breaker.New(3, 1, 5*time.Second)
+	b := circuit.New("example", circuit.Settings{
+		Trip:              circuit.ConsecutiveFailures(3),
+		HalfOpenSuccesses: 1,
+		OpenTimeout:       5 * time.Second,
+		OnStateChange: func(name string, from, to circuit.State) {
+			fmt.Printf("Breaker %v: %v -> %v\n", name, from, to)
+		},
+	})
 
 	for {
 		result := b.Run(func() error {
@@ -20,10 +28,10 @@ func main() {
 			return fmt.Errorf("Timeout")
 		})
 
-		switch result {
-		case nil:
+		switch {
+		case result == nil:
 			// 성공
-		case breaker.ErrBreakerOpen:
+		case errors.Is(result, circuit.ErrOpen):
 			// 회로 차단기가 열려 있기 때문에 코드를 실행하지 않는다.
 			fmt.Println("Breaker open")
 		default:
@@ -0,0 +1,66 @@
+package circuit
+
+import "sync"
+
+// Group holds a Breaker for every key, for example one for every downstream
+// endpoint, so that a failing endpoint does not open the breaker for the
+// others. All breakers share the same Settings.
+type Group struct {
+	settings Settings
+
+	mutex    sync.RWMutex
+	breakers map[string]*Breaker
+}
+
+// NewGroup creates a Group which creates its breakers with the given settings
+func NewGroup(settings Settings) *Group {
+	return &Group{
+		settings: settings,
+		breakers: map[string]*Breaker{},
+	}
+}
+
+// Get returns the breaker for the given key, creating it when needed
+func (g *Group) Get(key string) *Breaker {
+	g.mutex.RLock()
+	b, ok := g.breakers[key]
+	g.mutex.RUnlock()
+
+	if ok {
+		return b
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if b, ok := g.breakers[key]; ok {
+		return b
+	}
+
+	b = New(key, g.settings)
+	g.breakers[key] = b
+
+	return b
+}
+
+// Run calls fn through the breaker for the given key
+func (g *Group) Run(key string, fn func() error) error {
+	return g.Get(key).Run(fn)
+}
+
+// Metrics returns the metrics of every breaker by key
+func (g *Group) Metrics() map[string]Metrics {
+	g.mutex.RLock()
+	breakers := make(map[string]*Breaker, len(g.breakers))
+	for k, b := range g.breakers {
+		breakers[k] = b
+	}
+	g.mutex.RUnlock()
+
+	metrics := make(map[string]Metrics, len(breakers))
+	for k, b := range breakers {
+		metrics[k] = b.Metrics()
+	}
+
+	return metrics
+}
@@ -0,0 +1,66 @@
+package circuit
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Transport is an http.RoundTripper which sends every request through the
+// breaker of its host, requests to a host whose breaker is open fail with
+// ErrOpen without being sent.
+type Transport struct {
+	// Group holds the breakers, one for every key.
+	Group *Group
+	// Base sends the requests, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Key returns the key of the breaker for a request, defaults to the
+	// host of the request URL.
+	Key func(r *http.Request) string
+	// IsFailure decides whether a response counts as a failure, defaults to
+	// errors and responses with a 5xx status.
+	IsFailure func(resp *http.Response, err error) bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := req.URL.Host
+	if t.Key != nil {
+		key = t.Key(req)
+	}
+
+	done, err := t.Group.Get(key).Allow()
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("%v: %w", key, err)
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+
+	isFailure := t.IsFailure
+	if isFailure == nil {
+		isFailure = defaultIsFailure
+	}
+
+	if isFailure(resp, err) {
+		if err == nil {
+			done(fmt.Errorf("%v returned %v", key, resp.Status))
+		} else {
+			done(err)
+		}
+	} else {
+		done(nil)
+	}
+
+	return resp, err
+}
+
+func defaultIsFailure(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode >= http.StatusInternalServerError
+}
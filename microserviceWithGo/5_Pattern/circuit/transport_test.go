@@ -0,0 +1,66 @@
+package circuit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransportOpensBreakerForFailingHost(t *testing.T) {
+	var calls int32
+	failing := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		rw.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+	healthy := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	}))
+	defer healthy.Close()
+
+	client := &http.Client{Transport: &Transport{
+		Group: NewGroup(Settings{Trip: ConsecutiveFailures(2), OpenTimeout: time.Minute}),
+	}}
+
+	for i := 0; i < 2; i++ {
+		resp, err := client.Get(failing.URL)
+		assert.Nil(t, err)
+		resp.Body.Close()
+	}
+
+	_, err := client.Get(failing.URL)
+	assert.True(t, errors.Is(err, ErrOpen))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	resp, err := client.Get(healthy.URL)
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+func TestTransportUsesIsFailure(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer s.Close()
+
+	group := NewGroup(Settings{Trip: ConsecutiveFailures(1), OpenTimeout: time.Minute})
+	client := &http.Client{Transport: &Transport{
+		Group: group,
+		IsFailure: func(resp *http.Response, err error) bool {
+			return err != nil || resp.StatusCode == http.StatusTooManyRequests
+		},
+	}}
+
+	resp, err := client.Get(s.URL)
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	_, err = client.Get(s.URL)
+	assert.True(t, errors.Is(err, ErrOpen))
+}
@@ -0,0 +1,77 @@
+package circuit
+
+import "time"
+
+// Counts are the number of calls recorded in the rolling window
+type Counts struct {
+	Requests            int
+	Successes           int
+	Failures            int
+	ConsecutiveFailures int
+}
+
+type bucket struct {
+	successes int
+	failures  int
+}
+
+// window counts successes and failures over a rolling period of time, the
+// period is split into buckets and the oldest bucket is dropped as time
+// moves on.
+type window struct {
+	buckets      []bucket
+	bucketSize   time.Duration
+	current      int
+	currentStart time.Time
+}
+
+func newWindow(length time.Duration, buckets int, now time.Time) *window {
+	return &window{
+		buckets:      make([]bucket, buckets),
+		bucketSize:   length / time.Duration(buckets),
+		currentStart: now,
+	}
+}
+
+func (w *window) advance(now time.Time) {
+	for i := 0; i < len(w.buckets) && !now.Before(w.currentStart.Add(w.bucketSize)); i++ {
+		w.current = (w.current + 1) % len(w.buckets)
+		w.buckets[w.current] = bucket{}
+		w.currentStart = w.currentStart.Add(w.bucketSize)
+	}
+
+	// the whole window has expired
+	if !now.Before(w.currentStart.Add(w.bucketSize)) {
+		w.currentStart = now
+	}
+}
+
+func (w *window) record(now time.Time, success bool) {
+	w.advance(now)
+
+	if success {
+		w.buckets[w.current].successes++
+	} else {
+		w.buckets[w.current].failures++
+	}
+}
+
+func (w *window) counts(now time.Time) Counts {
+	w.advance(now)
+
+	c := Counts{}
+	for _, b := range w.buckets {
+		c.Successes += b.successes
+		c.Failures += b.failures
+	}
+	c.Requests = c.Successes + c.Failures
+
+	return c
+}
+
+func (w *window) reset(now time.Time) {
+	for i := range w.buckets {
+		w.buckets[i] = bucket{}
+	}
+	w.currentStart = now
+}
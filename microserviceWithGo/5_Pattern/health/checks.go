@@ -0,0 +1,184 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/VividCortex/ewma"
+)
+
+// LatencyCheck fails when the moving average of the observed request times
+// is above the threshold. Once it has been failing for the reset timeout the
+// average is reset so that the service gets the chance to recover, while it
+// is failing requests are usually rejected and no new times are observed.
+type LatencyCheck struct {
+	threshold    time.Duration
+	resetTimeout time.Duration
+
+	mutex          sync.Mutex
+	ma             ewma.MovingAverage
+	unhealthySince time.Time
+}
+
+// NewLatencyCheck creates a LatencyCheck for the given threshold and reset
+// timeout
+func NewLatencyCheck(threshold, resetTimeout time.Duration) *LatencyCheck {
+	return &LatencyCheck{
+		threshold:    threshold,
+		resetTimeout: resetTimeout,
+		ma:           ewma.NewMovingAverage(), // 단순 이동 평균 구현
+	}
+}
+
+// Observe adds the duration of a request to the moving average
+func (l *LatencyCheck) Observe(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.ma.Add(float64(d))
+}
+
+// Average returns the moving average of the observed request times
+func (l *LatencyCheck) Average() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return time.Duration(l.ma.Value())
+}
+
+// Check returns an error when the average is above the threshold
+func (l *LatencyCheck) Check(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	average := time.Duration(l.ma.Value())
+	if average < l.threshold {
+		l.unhealthySince = time.Time{}
+		return nil
+	}
+
+	if reset(&l.unhealthySince, l.resetTimeout) {
+		l.ma = ewma.NewMovingAverage() // 이동 평균 초기화
+		return nil
+	}
+
+	return fmt.Errorf("average request time %v is above %v", average, l.threshold)
+}
+
+// ErrorRateCheck fails when the moving average of the error rate is above the
+// maximum rate, it is reset after the reset timeout like LatencyCheck.
+type ErrorRateCheck struct {
+	maxRate      float64
+	minRequests  int
+	resetTimeout time.Duration
+
+	mutex          sync.Mutex
+	rate           float64
+	requests       int
+	unhealthySince time.Time
+}
+
+// NewErrorRateCheck creates an ErrorRateCheck which fails when more than
+// maxRate (0 to 1) of the requests fail. The check passes until at least
+// minRequests have been observed.
+func NewErrorRateCheck(maxRate float64, minRequests int, resetTimeout time.Duration) *ErrorRateCheck {
+	return &ErrorRateCheck{
+		maxRate:      maxRate,
+		minRequests:  minRequests,
+		resetTimeout: resetTimeout,
+	}
+}
+
+// Observe records the outcome of a request
+func (e *ErrorRateCheck) Observe(failed bool) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	value := 0.0
+	if failed {
+		value = 1.0
+	}
+
+	// the rate is the plain average until minRequests have been observed
+	// so that a single early error does not dominate the moving average
+	e.requests++
+	if e.requests <= e.minRequests {
+		e.rate += (value - e.rate) / float64(e.requests)
+		return
+	}
+
+	e.rate = value*ewma.DECAY + e.rate*(1-ewma.DECAY)
+}
+
+// Rate returns the moving average of the error rate
+func (e *ErrorRateCheck) Rate() float64 {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	return e.rate
+}
+
+// Check returns an error when the error rate is above the maximum
+func (e *ErrorRateCheck) Check(ctx context.Context) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if e.requests < e.minRequests || e.rate <= e.maxRate {
+		e.unhealthySince = time.Time{}
+		return nil
+	}
+
+	if reset(&e.unhealthySince, e.resetTimeout) {
+		e.rate = 0
+		e.requests = 0
+		return nil
+	}
+
+	return fmt.Errorf("error rate %.2f is above %.2f", e.rate, e.maxRate)
+}
+
+// reset records when a check started failing and returns true once it has
+// been failing for longer than timeout
+func reset(unhealthySince *time.Time, timeout time.Duration) bool {
+	now := time.Now()
+	if unhealthySince.IsZero() {
+		*unhealthySince = now
+	}
+
+	if timeout > 0 && now.Sub(*unhealthySince) >= timeout {
+		*unhealthySince = time.Time{}
+		return true
+	}
+
+	return false
+}
+
+// PingCheck creates a Check which sends a GET request to the given URL, any
+// 2xx response means the dependency is healthy.
+func PingCheck(client *http.Client, url string) Check {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return CheckFunc(func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("%v returned %v", url, resp.Status)
+		}
+
+		return nil
+	})
+}
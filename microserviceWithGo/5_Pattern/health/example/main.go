@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/health"
+)
+
+var threshold = 1000 * time.Millisecond
+var timeout = 1000 * time.Millisecond
+
+func main() {
+	latency := health.NewLatencyCheck(threshold, timeout)
+	errorRate := health.NewErrorRateCheck(0.5, 10, timeout)
+
+	h := health.New()
+	h.Register("latency", latency)
+	h.Register("errors", errorRate)
+
+	mainHandler := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+		fmt.Fprintf(rw, "Average request time: %f (ms)\n", float64(latency.Average())/1000000)
+	})
+
+	http.Handle("/", health.NewMiddleware(latency, errorRate, mainHandler))
+	http.Handle("/health", h)
+
+	http.ListenAndServe(":8080", nil)
+}
@@ -0,0 +1,166 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the health of a single check or of the whole service
+type Status string
+
+const (
+	// StatusOK is reported when a check passes
+	StatusOK Status = "ok"
+	// StatusUnhealthy is reported when a check fails
+	StatusUnhealthy Status = "unhealthy"
+)
+
+// DefaultTimeout is the time a check may take before it is reported as
+// unhealthy when Health.Timeout is not set.
+const DefaultTimeout = 5 * time.Second
+
+// Check is an interface to be implemented by anything whose health can be
+// reported, a nil error means the check passed.
+type Check interface {
+	Check(ctx context.Context) error
+}
+
+// CheckFunc allows the use of an ordinary function as a Check
+type CheckFunc func(ctx context.Context) error
+
+// Check calls f(ctx)
+func (f CheckFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the outcome of a single check in a Report
+type CheckResult struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// Report is the aggregated outcome of all checks, the service is only
+// healthy when every check passes.
+type Report struct {
+	Status Status                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Health holds a set of named checks and serves them as a JSON report
+type Health struct {
+	// Timeout for a single check, defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	mutex  sync.RWMutex
+	checks map[string]Check
+}
+
+// New creates an instance of Health without any checks
+func New() *Health {
+	return &Health{checks: map[string]Check{}}
+}
+
+// Register adds a check with the given name, a check which was already
+// registered with the same name is replaced.
+func (h *Health) Register(name string, check Check) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.checks[name] = check
+}
+
+// Report runs all checks concurrently and returns their results
+func (h *Health) Report(ctx context.Context) Report {
+	h.mutex.RLock()
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checks := make([]Check, len(names))
+	for i, name := range names {
+		checks[i] = h.checks[name]
+	}
+	h.mutex.RUnlock()
+
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]CheckResult, len(checks))
+	wg := sync.WaitGroup{}
+	wg.Add(len(checks))
+
+	for i, c := range checks {
+		go func(i int, c Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, c, timeout)
+		}(i, c)
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(names))}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != StatusOK {
+			report.Status = StatusUnhealthy
+		}
+	}
+
+	return report
+}
+
+func runCheck(ctx context.Context, c Check, timeout time.Duration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("check did not complete: %v", ctx.Err())
+	}
+
+	result := CheckResult{Status: StatusOK, Duration: time.Since(start).String()}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+
+	return result
+}
+
+// Healthy returns true when all checks pass
+func (h *Health) Healthy(ctx context.Context) bool {
+	return h.Report(ctx).Status == StatusOK
+}
+
+// ServeHTTP writes the Report as JSON, the status code is
+// http.StatusServiceUnavailable when any check fails
+func (h *Health) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	report := h.Report(r.Context())
+
+	rw.Header().Set("Content-Type", "application/json")
+	if report.Status != StatusOK {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		rw.WriteHeader(http.StatusOK)
+	}
+
+	encoder := json.NewEncoder(rw)
+	encoder.Encode(report)
+}
@@ -0,0 +1,170 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveReport(h *Health) (*httptest.ResponseRecorder, Report) {
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/health", nil))
+
+	report := Report{}
+	json.Unmarshal(rw.Body.Bytes(), &report)
+
+	return rw, report
+}
+
+func TestReturnsOKWhenAllChecksPass(t *testing.T) {
+	h := New()
+	h.Register("db", CheckFunc(func(ctx context.Context) error { return nil }))
+	h.Register("cache", CheckFunc(func(ctx context.Context) error { return nil }))
+
+	rw, report := serveReport(h)
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, StatusOK, report.Checks["db"].Status)
+	assert.Equal(t, StatusOK, report.Checks["cache"].Status)
+}
+
+func TestReturnsUnavailableWithFailingCheck(t *testing.T) {
+	h := New()
+	h.Register("db", CheckFunc(func(ctx context.Context) error { return errors.New("connection refused") }))
+	h.Register("cache", CheckFunc(func(ctx context.Context) error { return nil }))
+
+	rw, report := serveReport(h)
+
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+	assert.Equal(t, StatusUnhealthy, report.Status)
+	assert.Equal(t, CheckResult{Status: StatusUnhealthy, Error: "connection refused", Duration: report.Checks["db"].Duration}, report.Checks["db"])
+	assert.Equal(t, StatusOK, report.Checks["cache"].Status)
+}
+
+func TestReportsCheckWhichTimesOutAsUnhealthy(t *testing.T) {
+	h := New()
+	h.Timeout = 10 * time.Millisecond
+	h.Register("slow", CheckFunc(func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+
+	start := time.Now()
+	report := h.Report(context.Background())
+
+	assert.Equal(t, StatusUnhealthy, report.Checks["slow"].Status)
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
+
+func TestLatencyCheckFailsAboveThreshold(t *testing.T) {
+	l := NewLatencyCheck(100*time.Millisecond, time.Minute)
+
+	l.Observe(50 * time.Millisecond)
+	assert.Nil(t, l.Check(context.Background()))
+
+	for i := 0; i < 20; i++ {
+		l.Observe(500 * time.Millisecond)
+	}
+	assert.NotNil(t, l.Check(context.Background()))
+}
+
+func TestLatencyCheckResetsAfterTimeout(t *testing.T) {
+	l := NewLatencyCheck(100*time.Millisecond, 20*time.Millisecond)
+	l.Observe(time.Second)
+	assert.NotNil(t, l.Check(context.Background()))
+
+	time.Sleep(30 * time.Millisecond)
+
+	assert.Nil(t, l.Check(context.Background()))
+	assert.Equal(t, time.Duration(0), l.Average())
+}
+
+func TestErrorRateCheckPassesUntilMinRequests(t *testing.T) {
+	e := NewErrorRateCheck(0.1, 5, time.Minute)
+
+	for i := 0; i < 4; i++ {
+		e.Observe(true)
+	}
+	assert.Nil(t, e.Check(context.Background()))
+
+	e.Observe(true)
+	assert.NotNil(t, e.Check(context.Background()))
+}
+
+func TestErrorRateCheckPassesBelowMaxRate(t *testing.T) {
+	e := NewErrorRateCheck(0.5, 1, time.Minute)
+
+	for i := 0; i < 100; i++ {
+		e.Observe(i%10 == 0)
+	}
+
+	assert.Nil(t, e.Check(context.Background()))
+}
+
+func TestPingCheckFailsWhenDependencyIsUnavailable(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+
+	err := PingCheck(nil, s.URL).Check(context.Background())
+
+	assert.NotNil(t, err)
+}
+
+func TestMiddlewareRejectsRequestsWhileUnhealthy(t *testing.T) {
+	errorRate := NewErrorRateCheck(0.1, 1, time.Minute)
+	next := http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusInternalServerError)
+	})
+	m := NewMiddleware(nil, errorRate, next)
+
+	rw := httptest.NewRecorder()
+	m.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+
+	rw = httptest.NewRecorder()
+	m.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+}
+
+func TestMiddlewareKeepsFlusherAndHijacker(t *testing.T) {
+	hijacker := false
+	m := NewMiddleware(nil, nil, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.(http.Flusher).Flush()
+		_, hijacker = rw.(http.Hijacker)
+	}))
+
+	rw := httptest.NewRecorder()
+	m.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.True(t, rw.Flushed)
+	assert.True(t, hijacker)
+}
+
+func TestMiddlewareRecordsLatencyConcurrently(t *testing.T) {
+	latency := NewLatencyCheck(time.Second, time.Second)
+	m := NewMiddleware(latency, nil, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(time.Millisecond)
+	}))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+		}()
+	}
+	wg.Wait()
+
+	assert.True(t, latency.Average() >= time.Millisecond)
+}
@@ -0,0 +1,88 @@
+package health
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Middleware records the time and outcome of every request in a
+// LatencyCheck and an ErrorRateCheck. While either of them fails requests
+// are rejected with http.StatusServiceUnavailable.
+type Middleware struct {
+	latency   *LatencyCheck
+	errorRate *ErrorRateCheck
+	handler   http.Handler
+}
+
+// NewMiddleware creates a new instance of Middleware, latency or errorRate
+// may be nil when they are not needed.
+func NewMiddleware(latency *LatencyCheck, errorRate *ErrorRateCheck, next http.Handler) *Middleware {
+	return &Middleware{
+		latency:   latency,
+		errorRate: errorRate,
+		handler:   next,
+	}
+}
+
+func (m *Middleware) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if !m.healthy(r) {
+		http.Error(rw, "Service Unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	startTime := time.Now()
+	sr := &statusRecorder{ResponseWriter: rw, status: http.StatusOK}
+
+	m.handler.ServeHTTP(sr, r)
+
+	if m.latency != nil {
+		m.latency.Observe(time.Since(startTime))
+	}
+	if m.errorRate != nil {
+		m.errorRate.Observe(sr.status >= http.StatusInternalServerError)
+	}
+}
+
+func (m *Middleware) healthy(r *http.Request) bool {
+	if m.latency != nil && m.latency.Check(r.Context()) != nil {
+		return false
+	}
+	if m.errorRate != nil && m.errorRate.Check(r.Context()) != nil {
+		return false
+	}
+
+	return true
+}
+
+// statusRecorder captures the status code written by the next handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
+
+// Flush sends buffered data to the client when the wrapped ResponseWriter
+// supports it, so that streaming handlers keep working behind the middleware
+func (s *statusRecorder) Flush() {
+	if f, ok := s.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack takes over the connection when the wrapped ResponseWriter supports
+// it
+func (s *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := s.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("health: ResponseWriter does not support hijacking")
+	}
+
+	return h.Hijack()
+}
@@ -0,0 +1,113 @@
+package loadbalancing
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultReplicas is the number of virtual nodes placed on the ring for every
+// endpoint when ConsistentHashStrategy.Replicas is not set.
+const DefaultReplicas = 160
+
+// HashFunc hashes data onto the ring.
+type HashFunc func(data []byte) uint32
+
+// ConsistentHashStrategy implements KeyedStrategy using a hash ring with
+// virtual nodes. When the endpoints change only the keys owned by the added
+// or removed endpoints are remapped, every other key stays where it was.
+type ConsistentHashStrategy struct {
+	// Replicas is the number of virtual nodes per endpoint, more replicas
+	// give a more even distribution at the cost of a larger ring.
+	Replicas int
+	// Hash is used to place endpoints and keys on the ring, defaults to
+	// the first four bytes of the MD5 sum as used by ketama.
+	Hash HashFunc
+
+	mutex     sync.RWMutex
+	ring      []uint32 // sorted virtual node hashes
+	owners    map[uint32]int
+	endpoints []url.URL
+	next      uint32
+}
+
+// NextEndpoint returns an endpoint without a key, endpoints are returned in
+// turn so that keyless requests are spread evenly.
+func (c *ConsistentHashStrategy) NextEndpoint() url.URL {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if len(c.endpoints) == 0 {
+		return url.URL{}
+	}
+
+	n := atomic.AddUint32(&c.next, 1)
+	return c.endpoints[(n-1)%uint32(len(c.endpoints))]
+}
+
+// EndpointForKey returns the endpoint which owns the given key, this is the
+// first virtual node clockwise from the hash of the key.
+func (c *ConsistentHashStrategy) EndpointForKey(key string) url.URL {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	if len(c.ring) == 0 {
+		return url.URL{}
+	}
+
+	h := c.hash([]byte(key))
+	i := sort.Search(len(c.ring), func(i int) bool { return c.ring[i] >= h })
+	if i == len(c.ring) {
+		i = 0
+	}
+
+	return c.endpoints[c.owners[c.ring[i]]]
+}
+
+// SetEndpoints rebuilds the ring for the given endpoints
+func (c *ConsistentHashStrategy) SetEndpoints(endpoints []url.URL) {
+	replicas := c.Replicas
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+
+	ring := make([]uint32, 0, len(endpoints)*replicas)
+	owners := make(map[uint32]int, len(endpoints)*replicas)
+
+	for i, e := range endpoints {
+		id := e.String()
+		for r := 0; r < replicas; r++ {
+			h := c.hash([]byte(id + "#" + strconv.Itoa(r)))
+			// on a collision the lowest endpoint string wins so that the
+			// owner does not depend on the order of the endpoints
+			if o, ok := owners[h]; !ok {
+				ring = append(ring, h)
+			} else if endpoints[o].String() < id {
+				continue
+			}
+			owners[h] = i
+		}
+	}
+
+	sort.Slice(ring, func(i, j int) bool { return ring[i] < ring[j] })
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.ring = ring
+	c.owners = owners
+	c.endpoints = append([]url.URL(nil), endpoints...)
+}
+
+func (c *ConsistentHashStrategy) hash(data []byte) uint32 {
+	if c.Hash != nil {
+		return c.Hash(data)
+	}
+
+	sum := md5.Sum(data)
+	return binary.LittleEndian.Uint32(sum[:4])
+}
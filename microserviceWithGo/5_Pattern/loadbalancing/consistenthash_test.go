@@ -0,0 +1,147 @@
+package loadbalancing
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testKeys = 100000
+
+func newEndpoints(n int) []url.URL {
+	endpoints := make([]url.URL, n)
+	for i := 0; i < n; i++ {
+		endpoints[i] = url.URL{Scheme: "http", Host: fmt.Sprintf("10.0.0.%d:8080", i+1)}
+	}
+
+	return endpoints
+}
+
+func assignKeys(s KeyedStrategy) map[string]string {
+	assignment := make(map[string]string, testKeys)
+	for i := 0; i < testKeys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		assignment[key] = s.EndpointForKey(key).Host
+	}
+
+	return assignment
+}
+
+func TestReturnsSameEndpointForSameKey(t *testing.T) {
+	lb := NewLoadBalancer(&ConsistentHashStrategy{}, newEndpoints(5))
+
+	first := lb.GetEndpointForKey("Fat Freddy's Cat")
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, first, lb.GetEndpointForKey("Fat Freddy's Cat"))
+	}
+}
+
+func TestReturnsSameEndpointRegardlessOfEndpointOrder(t *testing.T) {
+	endpoints := newEndpoints(5)
+	reversed := make([]url.URL, len(endpoints))
+	for i, e := range endpoints {
+		reversed[len(endpoints)-1-i] = e
+	}
+
+	s1 := &ConsistentHashStrategy{}
+	s1.SetEndpoints(endpoints)
+	s2 := &ConsistentHashStrategy{}
+	s2.SetEndpoints(reversed)
+
+	assert.Equal(t, assignKeys(s1), assignKeys(s2))
+}
+
+func TestReturnsEmptyEndpointWhenNoEndpoints(t *testing.T) {
+	s := &ConsistentHashStrategy{}
+	s.SetEndpoints(nil)
+
+	assert.Equal(t, url.URL{}, s.EndpointForKey("Garfield"))
+	assert.Equal(t, url.URL{}, s.NextEndpoint())
+}
+
+func TestNextEndpointRotatesThroughEndpoints(t *testing.T) {
+	s := &ConsistentHashStrategy{}
+	s.SetEndpoints(newEndpoints(3))
+
+	seen := map[string]int{}
+	for i := 0; i < 9; i++ {
+		seen[s.NextEndpoint().Host]++
+	}
+
+	assert.Equal(t, 3, len(seen))
+	for _, n := range seen {
+		assert.Equal(t, 3, n)
+	}
+}
+
+func TestOnlyKeysForNewEndpointMoveWhenEndpointAdded(t *testing.T) {
+	endpoints := newEndpoints(10)
+	s := &ConsistentHashStrategy{}
+	s.SetEndpoints(endpoints)
+	before := assignKeys(s)
+
+	added := url.URL{Scheme: "http", Host: "10.0.0.11:8080"}
+	s.SetEndpoints(append(endpoints, added))
+	after := assignKeys(s)
+
+	moved := 0
+	for key, host := range after {
+		if before[key] != host {
+			moved++
+			assert.Equal(t, added.Host, host, "key %v moved between existing endpoints", key)
+		}
+	}
+
+	// ideally 1/11 of the keys move to the new endpoint
+	ratio := float64(moved) / testKeys
+	t.Logf("%d of %d keys moved (%.2f%%, ideal %.2f%%)", moved, testKeys, ratio*100, 100.0/11)
+	assert.InDelta(t, 1.0/11, ratio, 0.03)
+}
+
+func TestOnlyKeysForRemovedEndpointMoveWhenEndpointRemoved(t *testing.T) {
+	endpoints := newEndpoints(10)
+	s := &ConsistentHashStrategy{}
+	s.SetEndpoints(endpoints)
+	before := assignKeys(s)
+
+	removed := endpoints[3]
+	s.SetEndpoints(append(append([]url.URL{}, endpoints[:3]...), endpoints[4:]...))
+	after := assignKeys(s)
+
+	moved := 0
+	for key, host := range after {
+		if before[key] != host {
+			moved++
+			assert.Equal(t, removed.Host, before[key], "key %v was not owned by the removed endpoint", key)
+		}
+	}
+
+	ratio := float64(moved) / testKeys
+	t.Logf("%d of %d keys moved (%.2f%%, ideal %.2f%%)", moved, testKeys, ratio*100, 100.0/10)
+	assert.InDelta(t, 1.0/10, ratio, 0.03)
+}
+
+func TestDistributesKeysEvenlyAcrossEndpoints(t *testing.T) {
+	endpoints := newEndpoints(10)
+	s := &ConsistentHashStrategy{}
+	s.SetEndpoints(endpoints)
+
+	load := map[string]int{}
+	for _, host := range assignKeys(s) {
+		load[host]++
+	}
+
+	mean := float64(testKeys) / float64(len(endpoints))
+	sumSquares := 0.0
+	for _, e := range endpoints {
+		deviation := (float64(load[e.Host]) - mean) / mean
+		sumSquares += deviation * deviation
+		// no endpoint should carry more than 25% above or below its share
+		assert.InDelta(t, 0, deviation, 0.25, "endpoint %v has %d keys", e.Host, load[e.Host])
+	}
+
+	t.Logf("relative standard deviation of load: %.2f%%", math.Sqrt(sumSquares/float64(len(endpoints)))*100)
+}
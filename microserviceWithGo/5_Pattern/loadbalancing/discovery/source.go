@@ -0,0 +1,176 @@
+package discovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Source is an interface to be implemented by discovery sources which
+// return the current endpoints of a service.
+type Source interface {
+	Endpoints(ctx context.Context) ([]url.URL, error)
+}
+
+// StaticSource is a Source which always returns the same endpoints
+type StaticSource []url.URL
+
+// Endpoints returns the static endpoints
+func (s StaticSource) Endpoints(ctx context.Context) ([]url.URL, error) {
+	return append([]url.URL(nil), s...), nil
+}
+
+// endpointsFile is the format of the files read by FileSource
+//
+//	{"endpoints": ["http://10.0.0.1:8080", "10.0.0.2:8080"]}
+type endpointsFile struct {
+	Endpoints []string `json:"endpoints" yaml:"endpoints"`
+}
+
+// FileSource is a Source which reads the endpoints from a JSON or YAML file,
+// the format is chosen by the extension of the file. The file is only parsed
+// again when its modification time or size has changed.
+type FileSource struct {
+	Path string
+
+	mutex     sync.Mutex
+	modTime   time.Time
+	size      int64
+	endpoints []url.URL
+}
+
+// Endpoints returns the endpoints listed in the file
+func (f *FileSource) Endpoints(ctx context.Context) ([]url.URL, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	info, err := os.Stat(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.endpoints != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return append([]url.URL(nil), f.endpoints...), nil
+	}
+
+	data, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file endpointsFile
+	switch strings.ToLower(filepath.Ext(f.Path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &file)
+	default:
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("discovery: unable to parse %v: %v", f.Path, err)
+	}
+
+	endpoints := make([]url.URL, 0, len(file.Endpoints))
+	for _, e := range file.Endpoints {
+		u, err := parseEndpoint(e)
+		if err != nil {
+			return nil, fmt.Errorf("discovery: invalid endpoint in %v: %v", f.Path, err)
+		}
+		endpoints = append(endpoints, u)
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.endpoints = endpoints
+
+	return append([]url.URL(nil), endpoints...), nil
+}
+
+// parseEndpoint parses a URL or a bare host:port
+func parseEndpoint(s string) (url.URL, error) {
+	if !strings.Contains(s, "://") {
+		s = "//" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return url.URL{}, err
+	}
+	if u.Host == "" {
+		return url.URL{}, fmt.Errorf("missing host in %q", s)
+	}
+
+	return *u, nil
+}
+
+// DNSSource is a Source which looks up the endpoints in DNS. When Service is
+// set SRV records for _Service._Proto.Name are used, otherwise the A and AAAA
+// records of Name are combined with Port.
+type DNSSource struct {
+	Name    string
+	Service string
+	// Proto is the protocol of SRV records, defaults to "tcp".
+	Proto string
+	// Port is used for A and AAAA records.
+	Port int
+	// Scheme is set on every endpoint, defaults to "http".
+	Scheme string
+	// Resolver is used for the lookups, defaults to net.DefaultResolver. Use
+	// a resolver with a custom Dial to query a specific DNS server.
+	Resolver *net.Resolver
+}
+
+// Endpoints looks up the endpoints in DNS
+func (d *DNSSource) Endpoints(ctx context.Context) ([]url.URL, error) {
+	resolver := d.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	scheme := d.Scheme
+	if scheme == "" {
+		scheme = "http"
+	}
+
+	if d.Service != "" {
+		proto := d.Proto
+		if proto == "" {
+			proto = "tcp"
+		}
+
+		_, records, err := resolver.LookupSRV(ctx, d.Service, proto, d.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		endpoints := make([]url.URL, 0, len(records))
+		for _, r := range records {
+			host := strings.TrimSuffix(r.Target, ".")
+			endpoints = append(endpoints, url.URL{Scheme: scheme, Host: net.JoinHostPort(host, strconv.Itoa(int(r.Port)))})
+		}
+
+		return endpoints, nil
+	}
+
+	addrs, err := resolver.LookupIPAddr(ctx, d.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := make([]url.URL, 0, len(addrs))
+	for _, a := range addrs {
+		endpoints = append(endpoints, url.URL{Scheme: scheme, Host: net.JoinHostPort(a.IP.String(), strconv.Itoa(d.Port))})
+	}
+
+	return endpoints, nil
+}
@@ -0,0 +1,188 @@
+package discovery
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestStaticSourceReturnsEndpoints(t *testing.T) {
+	s := StaticSource{{Host: "www.google.com"}}
+
+	endpoints, err := s.Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []url.URL{{Host: "www.google.com"}}, endpoints)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	assert.Nil(t, err)
+}
+
+func TestFileSourceReadsJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	writeFile(t, path, `{"endpoints": ["http://10.0.0.1:8080", "10.0.0.2:8080"]}`)
+
+	endpoints, err := (&FileSource{Path: path}).Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []url.URL{
+		{Scheme: "http", Host: "10.0.0.1:8080"},
+		{Host: "10.0.0.2:8080"},
+	}, endpoints)
+}
+
+func TestFileSourceReadsYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.yaml")
+	writeFile(t, path, "endpoints:\n  - http://10.0.0.1:8080\n  - https://10.0.0.2\n")
+
+	endpoints, err := (&FileSource{Path: path}).Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, []url.URL{
+		{Scheme: "http", Host: "10.0.0.1:8080"},
+		{Scheme: "https", Host: "10.0.0.2"},
+	}, endpoints)
+}
+
+func TestFileSourceReadsFileAgainWhenChanged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	writeFile(t, path, `{"endpoints": ["10.0.0.1:8080"]}`)
+	source := &FileSource{Path: path}
+	source.Endpoints(context.Background())
+
+	writeFile(t, path, `{"endpoints": ["10.0.0.1:8080", "10.0.0.2:8080"]}`)
+	later := time.Now().Add(time.Second)
+	os.Chtimes(path, later, later)
+
+	endpoints, err := source.Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(endpoints))
+}
+
+func TestFileSourceReturnsErrorForInvalidFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "endpoints.json")
+	writeFile(t, path, `{"endpoints": [`)
+
+	_, err := (&FileSource{Path: path}).Endpoints(context.Background())
+
+	assert.NotNil(t, err)
+}
+
+// startDNSStub starts a DNS server on loopback which answers with the given
+// records and returns a resolver which uses it
+func startDNSStub(t *testing.T, answers []dnsmessage.Resource) *net.Resolver {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			var request dnsmessage.Message
+			if err := request.Unpack(buf[:n]); err != nil || len(request.Questions) == 0 {
+				continue
+			}
+
+			q := request.Questions[0]
+			response := dnsmessage.Message{
+				Header:    dnsmessage.Header{ID: request.ID, Response: true, Authoritative: true},
+				Questions: request.Questions,
+			}
+			for _, a := range answers {
+				if a.Header.Type == q.Type && a.Header.Name == q.Name {
+					response.Answers = append(response.Answers, a)
+				}
+			}
+			if len(response.Answers) == 0 && q.Type != dnsmessage.TypeAAAA {
+				response.Header.RCode = dnsmessage.RCodeNameError
+			}
+
+			packed, err := response.Pack()
+			if err == nil {
+				conn.WriteTo(packed, addr)
+			}
+		}
+	}()
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			d := net.Dialer{}
+			return d.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
+
+func header(name string, t dnsmessage.Type) dnsmessage.ResourceHeader {
+	return dnsmessage.ResourceHeader{
+		Name:  dnsmessage.MustNewName(name),
+		Type:  t,
+		Class: dnsmessage.ClassINET,
+		TTL:   60,
+	}
+}
+
+func TestDNSSourceLooksUpARecords(t *testing.T) {
+	resolver := startDNSStub(t, []dnsmessage.Resource{
+		{Header: header("kittens.local.", dnsmessage.TypeA), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}},
+		{Header: header("kittens.local.", dnsmessage.TypeA), Body: &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}},
+	})
+	source := &DNSSource{Name: "kittens.local.", Port: 8080, Resolver: resolver}
+
+	endpoints, err := source.Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []url.URL{
+		{Scheme: "http", Host: "10.0.0.1:8080"},
+		{Scheme: "http", Host: "10.0.0.2:8080"},
+	}, endpoints)
+}
+
+func TestDNSSourceLooksUpSRVRecords(t *testing.T) {
+	resolver := startDNSStub(t, []dnsmessage.Resource{
+		{
+			Header: header("_http._tcp.kittens.local.", dnsmessage.TypeSRV),
+			Body:   &dnsmessage.SRVResource{Priority: 1, Weight: 10, Port: 9000, Target: dnsmessage.MustNewName("node1.kittens.local.")},
+		},
+		{
+			Header: header("_http._tcp.kittens.local.", dnsmessage.TypeSRV),
+			Body:   &dnsmessage.SRVResource{Priority: 1, Weight: 10, Port: 9001, Target: dnsmessage.MustNewName("node2.kittens.local.")},
+		},
+	})
+	source := &DNSSource{Name: "kittens.local.", Service: "http", Resolver: resolver}
+
+	endpoints, err := source.Endpoints(context.Background())
+
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []url.URL{
+		{Scheme: "http", Host: "node1.kittens.local:9000"},
+		{Scheme: "http", Host: "node2.kittens.local:9001"},
+	}, endpoints)
+}
+
+func TestDNSSourceReturnsErrorForUnknownName(t *testing.T) {
+	resolver := startDNSStub(t, nil)
+	source := &DNSSource{Name: "unknown.local.", Port: 8080, Resolver: resolver}
+
+	_, err := source.Endpoints(context.Background())
+
+	assert.NotNil(t, err)
+}
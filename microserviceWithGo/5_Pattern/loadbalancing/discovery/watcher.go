@@ -0,0 +1,175 @@
+package discovery
+
+import (
+	"context"
+	"log"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Updater is an interface to be implemented by anything which uses the
+// discovered endpoints, loadbalancing.LoadBalancer implements it.
+type Updater interface {
+	UpdateEndpoints([]url.URL)
+}
+
+// Event describes a change of the discovered endpoints
+type Event struct {
+	Added     []url.URL
+	Removed   []url.URL
+	Endpoints []url.URL // all endpoints after the change
+}
+
+// Watcher polls a Source and passes the endpoints to an Updater whenever
+// they change.
+type Watcher struct {
+	source   Source
+	updater  Updater
+	interval time.Duration
+	events   chan Event
+
+	mutex     sync.Mutex
+	endpoints map[string]url.URL
+	started   bool
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// NewWatcher creates a Watcher which polls the source every interval
+func NewWatcher(source Source, updater Updater, interval time.Duration) *Watcher {
+	return &Watcher{
+		source:   source,
+		updater:  updater,
+		interval: interval,
+		events:   make(chan Event, 16),
+	}
+}
+
+// Events returns the channel on which changes are published. Events are
+// dropped when the channel is full, every Event contains all endpoints so a
+// reader which falls behind only misses the intermediate states.
+func (w *Watcher) Events() <-chan Event {
+	return w.events
+}
+
+// Refresh queries the source once and updates the endpoints when they have
+// changed. When the source returns an error the current endpoints are kept.
+func (w *Watcher) Refresh(ctx context.Context) error {
+	endpoints, err := w.source.Endpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	current := make(map[string]url.URL, len(endpoints))
+	for _, e := range endpoints {
+		current[e.String()] = e
+	}
+
+	event := Event{}
+	for k, e := range current {
+		if _, ok := w.endpoints[k]; !ok {
+			event.Added = append(event.Added, e)
+		}
+	}
+	for k, e := range w.endpoints {
+		if _, ok := current[k]; !ok {
+			event.Removed = append(event.Removed, e)
+		}
+	}
+
+	// the first refresh always sets the endpoints, even when there are none
+	if w.endpoints != nil && len(event.Added) == 0 && len(event.Removed) == 0 {
+		return nil
+	}
+
+	w.endpoints = current
+	event.Endpoints = sortedEndpoints(current)
+	sortURLs(event.Added)
+	sortURLs(event.Removed)
+
+	w.updater.UpdateEndpoints(append([]url.URL(nil), event.Endpoints...))
+
+	select {
+	case w.events <- event:
+	default:
+	}
+
+	return nil
+}
+
+// Start refreshes the endpoints every interval until Stop is called
+func (w *Watcher) Start() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.started {
+		return
+	}
+
+	w.started = true
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+
+	go w.run(w.stop, w.done)
+}
+
+// Stop stops polling the source
+func (w *Watcher) Stop() {
+	w.mutex.Lock()
+	if !w.started {
+		w.mutex.Unlock()
+		return
+	}
+	w.started = false
+	stop, done := w.stop, w.done
+	w.mutex.Unlock()
+
+	close(stop)
+	<-done
+}
+
+func (w *Watcher) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-stop
+		cancel()
+	}()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Refresh(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Unable to refresh endpoints: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func sortedEndpoints(endpoints map[string]url.URL) []url.URL {
+	sorted := make([]url.URL, 0, len(endpoints))
+	for _, e := range endpoints {
+		sorted = append(sorted, e)
+	}
+	sortURLs(sorted)
+
+	return sorted
+}
+
+func sortURLs(urls []url.URL) {
+	sort.Slice(urls, func(i, j int) bool { return urls[i].String() < urls[j].String() })
+}
@@ -0,0 +1,130 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/loadbalancing"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSource struct {
+	mutex     sync.Mutex
+	endpoints []url.URL
+	err       error
+}
+
+func (s *testSource) set(endpoints []url.URL, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.endpoints, s.err = endpoints, err
+}
+
+func (s *testSource) Endpoints(ctx context.Context) ([]url.URL, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.endpoints, s.err
+}
+
+type testUpdater struct {
+	mutex   sync.Mutex
+	updates [][]url.URL
+}
+
+func (u *testUpdater) UpdateEndpoints(endpoints []url.URL) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	u.updates = append(u.updates, endpoints)
+}
+
+func (u *testUpdater) count() int {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+
+	return len(u.updates)
+}
+
+var (
+	e1 = url.URL{Scheme: "http", Host: "10.0.0.1:8080"}
+	e2 = url.URL{Scheme: "http", Host: "10.0.0.2:8080"}
+	e3 = url.URL{Scheme: "http", Host: "10.0.0.3:8080"}
+)
+
+func TestRefreshUpdatesEndpointsAndEmitsEvent(t *testing.T) {
+	source := &testSource{endpoints: []url.URL{e2, e1}}
+	updater := &testUpdater{}
+	w := NewWatcher(source, updater, time.Second)
+
+	err := w.Refresh(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, [][]url.URL{{e1, e2}}, updater.updates)
+	assert.Equal(t, Event{Added: []url.URL{e1, e2}, Endpoints: []url.URL{e1, e2}}, <-w.Events())
+}
+
+func TestRefreshEmitsDiff(t *testing.T) {
+	source := &testSource{endpoints: []url.URL{e1, e2}}
+	w := NewWatcher(source, &testUpdater{}, time.Second)
+	w.Refresh(context.Background())
+	<-w.Events()
+
+	source.set([]url.URL{e2, e3}, nil)
+	w.Refresh(context.Background())
+
+	assert.Equal(t, Event{Added: []url.URL{e3}, Removed: []url.URL{e1}, Endpoints: []url.URL{e2, e3}}, <-w.Events())
+}
+
+func TestRefreshDoesNothingWhenEndpointsAreUnchanged(t *testing.T) {
+	source := &testSource{endpoints: []url.URL{e1, e2}}
+	updater := &testUpdater{}
+	w := NewWatcher(source, updater, time.Second)
+	w.Refresh(context.Background())
+
+	source.set([]url.URL{e2, e1}, nil)
+	w.Refresh(context.Background())
+
+	assert.Equal(t, 1, updater.count())
+	assert.Equal(t, 1, len(w.Events()))
+}
+
+func TestRefreshKeepsEndpointsWhenSourceFails(t *testing.T) {
+	source := &testSource{endpoints: []url.URL{e1}}
+	updater := &testUpdater{}
+	w := NewWatcher(source, updater, time.Second)
+	w.Refresh(context.Background())
+
+	source.set(nil, errors.New("lookup failed"))
+	err := w.Refresh(context.Background())
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, updater.count())
+}
+
+func TestStartPollsSourceAndUpdatesLoadBalancer(t *testing.T) {
+	source := &testSource{endpoints: []url.URL{e1}}
+	lb := loadbalancing.NewLoadBalancer(&loadbalancing.RandomStrategy{}, nil)
+	w := NewWatcher(source, lb, 10*time.Millisecond)
+
+	w.Start()
+	defer w.Stop()
+
+	<-w.Events()
+	assert.Equal(t, e1, lb.GetEndpoint())
+
+	source.set([]url.URL{e2}, nil)
+
+	select {
+	case event := <-w.Events():
+		assert.Equal(t, []url.URL{e2}, event.Added)
+		assert.Equal(t, e2, lb.GetEndpoint())
+	case <-time.After(time.Second):
+		t.Fatal("Expected an event after the source changed")
+	}
+}
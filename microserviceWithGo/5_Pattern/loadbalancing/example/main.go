@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/loadbalancing"
+)
+
+func main() {
+	endpoints := []url.URL{
+		{Host: "www.google.com"},
+		{Host: "www.google.co.uk"},
+	}
+
+	lb := loadbalancing.NewLoadBalancer(&loadbalancing.RandomStrategy{}, endpoints)
+
+	fmt.Println(lb.GetEndpoint())
+
+	// 같은 키는 항상 같은 엔드포인트로 전달된다.
+	sticky := loadbalancing.NewLoadBalancer(&loadbalancing.ConsistentHashStrategy{}, endpoints)
+
+	fmt.Println(sticky.GetEndpointForKey("user-1"))
+	fmt.Println(sticky.GetEndpointForKey("user-1"))
+}
@@ -0,0 +1,372 @@
+package loadbalancing
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HealthCheckConfig configures the active and passive health checking
+// performed by HealthCheckedStrategy, zero values are replaced with defaults.
+type HealthCheckConfig struct {
+	// Path is requested with an HTTP GET on every endpoint, any 2xx response
+	// is a successful probe. Defaults to "/health".
+	Path string
+	// Interval between two rounds of probes, defaults to 10 seconds.
+	Interval time.Duration
+	// Timeout for a single probe, defaults to 1 second.
+	Timeout time.Duration
+	// HealthyThreshold is the number of consecutive successful probes before
+	// an unhealthy endpoint is restored, defaults to 2.
+	HealthyThreshold int
+	// UnhealthyThreshold is the number of consecutive failed probes before
+	// an endpoint is ejected, defaults to 3.
+	UnhealthyThreshold int
+	// ConsecutiveFailures is the number of consecutive failed calls reported
+	// through Report before an endpoint is ejected as an outlier, defaults
+	// to 5.
+	ConsecutiveFailures int
+	// EjectionTime is how long an outlier stays ejected before it is given
+	// traffic again, defaults to 30 seconds.
+	EjectionTime time.Duration
+	// Client is used to send the probes, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+func (c HealthCheckConfig) withDefaults() HealthCheckConfig {
+	if c.Path == "" {
+		c.Path = "/health"
+	}
+	if c.Interval <= 0 {
+		c.Interval = 10 * time.Second
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 1 * time.Second
+	}
+	if c.HealthyThreshold <= 0 {
+		c.HealthyThreshold = 2
+	}
+	if c.UnhealthyThreshold <= 0 {
+		c.UnhealthyThreshold = 3
+	}
+	if c.ConsecutiveFailures <= 0 {
+		c.ConsecutiveFailures = 5
+	}
+	if c.EjectionTime <= 0 {
+		c.EjectionTime = 30 * time.Second
+	}
+	if c.Client == nil {
+		c.Client = http.DefaultClient
+	}
+
+	return c
+}
+
+type endpointHealth struct {
+	endpoint url.URL
+
+	// active health checking
+	healthy        bool
+	probeSuccesses int
+	probeFailures  int
+
+	// passive outlier detection
+	callFailures int
+	ejectedUntil time.Time
+}
+
+func (e *endpointHealth) available(now time.Time) bool {
+	return e.healthy && !now.Before(e.ejectedUntil)
+}
+
+// HealthCheckedStrategy wraps a Strategy and only passes the healthy
+// endpoints on to it. Endpoints are probed periodically once Start has been
+// called and are ejected as outliers when too many calls reported through
+// Report fail in a row.
+//
+// When no endpoint is healthy all endpoints are passed to the wrapped
+// strategy, sending traffic to a possibly unhealthy endpoint is better than
+// failing every request.
+type HealthCheckedStrategy struct {
+	strategy Strategy
+	config   HealthCheckConfig
+
+	mutex     sync.Mutex
+	endpoints []*endpointHealth
+	available []url.URL
+	// nextExpiry is when the next ejected outlier should be restored
+	nextExpiry time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewHealthCheckedStrategy creates a HealthCheckedStrategy which ejects
+// unhealthy endpoints from the given strategy.
+func NewHealthCheckedStrategy(strategy Strategy, config HealthCheckConfig) *HealthCheckedStrategy {
+	return &HealthCheckedStrategy{
+		strategy: strategy,
+		config:   config.withDefaults(),
+	}
+}
+
+// NextEndpoint returns a healthy endpoint from the wrapped strategy
+func (h *HealthCheckedStrategy) NextEndpoint() url.URL {
+	h.restoreExpired()
+	return h.strategy.NextEndpoint()
+}
+
+// EndpointForKey returns a healthy endpoint for the given key when the
+// wrapped strategy is a KeyedStrategy
+func (h *HealthCheckedStrategy) EndpointForKey(key string) url.URL {
+	h.restoreExpired()
+	if ks, ok := h.strategy.(KeyedStrategy); ok {
+		return ks.EndpointForKey(key)
+	}
+
+	return h.strategy.NextEndpoint()
+}
+
+// SetEndpoints sets the endpoints to check, the health of endpoints which
+// were already known is kept and new endpoints start as healthy.
+func (h *HealthCheckedStrategy) SetEndpoints(endpoints []url.URL) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	known := make(map[string]*endpointHealth, len(h.endpoints))
+	for _, e := range h.endpoints {
+		known[e.endpoint.String()] = e
+	}
+
+	h.endpoints = make([]*endpointHealth, 0, len(endpoints))
+	for _, u := range endpoints {
+		e, ok := known[u.String()]
+		if !ok {
+			e = &endpointHealth{endpoint: u, healthy: true}
+		}
+		h.endpoints = append(h.endpoints, e)
+	}
+
+	h.update(time.Now(), true)
+}
+
+// Healthy returns the endpoints which are currently passed to the wrapped
+// strategy.
+func (h *HealthCheckedStrategy) Healthy() []url.URL {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.update(time.Now(), false)
+
+	return append([]url.URL(nil), h.available...)
+}
+
+// Report records the outcome of a call to the given endpoint, a nil error is
+// a success. Once ConsecutiveFailures calls have failed the endpoint is
+// ejected for EjectionTime.
+func (h *HealthCheckedStrategy) Report(endpoint url.URL, err error) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	e := h.find(endpoint)
+	if e == nil {
+		return
+	}
+
+	if err == nil {
+		e.callFailures = 0
+		return
+	}
+
+	e.callFailures++
+	if e.callFailures >= h.config.ConsecutiveFailures {
+		e.callFailures = 0
+		now := time.Now()
+		e.ejectedUntil = now.Add(h.config.EjectionTime)
+		h.update(now, false)
+	}
+}
+
+// Start probes all endpoints every Interval until Stop is called
+func (h *HealthCheckedStrategy) Start() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	if h.stop != nil {
+		return
+	}
+
+	h.stop = make(chan struct{})
+	h.done = make(chan struct{})
+
+	go h.run(h.stop, h.done)
+}
+
+// Stop stops the probes started by Start and waits for a running round of
+// probes to finish.
+func (h *HealthCheckedStrategy) Stop() {
+	h.mutex.Lock()
+	stop, done := h.stop, h.done
+	h.stop, h.done = nil, nil
+	h.mutex.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	close(stop)
+	<-done
+}
+
+func (h *HealthCheckedStrategy) run(stop, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		h.Check()
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Check probes every endpoint once and updates their health
+func (h *HealthCheckedStrategy) Check() {
+	h.mutex.Lock()
+	endpoints := make([]url.URL, len(h.endpoints))
+	for i, e := range h.endpoints {
+		endpoints[i] = e.endpoint
+	}
+	h.mutex.Unlock()
+
+	results := make([]bool, len(endpoints))
+	wg := sync.WaitGroup{}
+	wg.Add(len(endpoints))
+
+	for i, u := range endpoints {
+		go func(i int, u url.URL) {
+			defer wg.Done()
+			results[i] = h.probe(u)
+		}(i, u)
+	}
+
+	wg.Wait()
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for i, u := range endpoints {
+		e := h.find(u)
+		if e == nil {
+			continue // removed while probing
+		}
+
+		if results[i] {
+			e.probeFailures = 0
+			e.probeSuccesses++
+			if !e.healthy && e.probeSuccesses >= h.config.HealthyThreshold {
+				e.healthy = true
+			}
+		} else {
+			e.probeSuccesses = 0
+			e.probeFailures++
+			if e.healthy && e.probeFailures >= h.config.UnhealthyThreshold {
+				e.healthy = false
+			}
+		}
+	}
+
+	h.update(time.Now(), false)
+}
+
+func (h *HealthCheckedStrategy) probe(endpoint url.URL) bool {
+	target := url.URL{Scheme: endpoint.Scheme, Host: endpoint.Host, Path: h.config.Path}
+	if target.Scheme == "" {
+		target.Scheme = "http"
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), h.config.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return false
+	}
+
+	resp, err := h.config.Client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode >= 200 && resp.StatusCode < 300
+}
+
+func (h *HealthCheckedStrategy) find(endpoint url.URL) *endpointHealth {
+	for _, e := range h.endpoints {
+		if e.endpoint.String() == endpoint.String() {
+			return e
+		}
+	}
+
+	return nil
+}
+
+func (h *HealthCheckedStrategy) restoreExpired() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	now := time.Now()
+	if !h.nextExpiry.IsZero() && !now.Before(h.nextExpiry) {
+		h.update(now, false)
+	}
+}
+
+// update passes the available endpoints to the wrapped strategy when they
+// have changed, the caller must hold the mutex.
+func (h *HealthCheckedStrategy) update(now time.Time, force bool) {
+	available := make([]url.URL, 0, len(h.endpoints))
+	h.nextExpiry = time.Time{}
+	for _, e := range h.endpoints {
+		if e.available(now) {
+			available = append(available, e.endpoint)
+		}
+		if now.Before(e.ejectedUntil) && (h.nextExpiry.IsZero() || e.ejectedUntil.Before(h.nextExpiry)) {
+			h.nextExpiry = e.ejectedUntil
+		}
+	}
+
+	if len(available) == 0 {
+		for _, e := range h.endpoints {
+			available = append(available, e.endpoint)
+		}
+	}
+
+	if !force && sameEndpoints(h.available, available) {
+		return
+	}
+
+	h.available = available
+	h.strategy.SetEndpoints(append([]url.URL(nil), available...))
+}
+
+func sameEndpoints(a, b []url.URL) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].String() != b[i].String() {
+			return false
+		}
+	}
+
+	return true
+}
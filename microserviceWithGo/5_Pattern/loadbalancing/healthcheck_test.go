@@ -0,0 +1,154 @@
+package loadbalancing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBackend struct {
+	server  *httptest.Server
+	healthy int32
+	probes  int32
+}
+
+func newTestBackend(t *testing.T) *testBackend {
+	b := &testBackend{healthy: 1}
+	b.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		atomic.AddInt32(&b.probes, 1)
+		if atomic.LoadInt32(&b.healthy) == 0 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		rw.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(b.server.Close)
+
+	return b
+}
+
+func (b *testBackend) setHealthy(healthy bool) {
+	if healthy {
+		atomic.StoreInt32(&b.healthy, 1)
+	} else {
+		atomic.StoreInt32(&b.healthy, 0)
+	}
+}
+
+func (b *testBackend) endpoint() url.URL {
+	u, _ := url.Parse(b.server.URL)
+	return *u
+}
+
+func setupHealthCheck(t *testing.T, config HealthCheckConfig) (*HealthCheckedStrategy, *testBackend, *testBackend) {
+	b1, b2 := newTestBackend(t), newTestBackend(t)
+	h := NewHealthCheckedStrategy(&RandomStrategy{}, config)
+	h.SetEndpoints([]url.URL{b1.endpoint(), b2.endpoint()})
+
+	return h, b1, b2
+}
+
+func TestAllEndpointsAreHealthyInitially(t *testing.T) {
+	h, b1, b2 := setupHealthCheck(t, HealthCheckConfig{})
+
+	assert.Equal(t, []url.URL{b1.endpoint(), b2.endpoint()}, h.Healthy())
+}
+
+func TestEjectsEndpointAfterUnhealthyThresholdProbes(t *testing.T) {
+	h, b1, b2 := setupHealthCheck(t, HealthCheckConfig{UnhealthyThreshold: 2})
+	b1.setHealthy(false)
+
+	h.Check()
+	assert.Equal(t, 2, len(h.Healthy()))
+
+	h.Check()
+	assert.Equal(t, []url.URL{b2.endpoint()}, h.Healthy())
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, b2.endpoint(), h.NextEndpoint())
+	}
+}
+
+func TestRestoresEndpointAfterHealthyThresholdProbes(t *testing.T) {
+	h, b1, _ := setupHealthCheck(t, HealthCheckConfig{UnhealthyThreshold: 1, HealthyThreshold: 2})
+	b1.setHealthy(false)
+	h.Check()
+
+	b1.setHealthy(true)
+	h.Check()
+	assert.Equal(t, 1, len(h.Healthy()))
+
+	h.Check()
+	assert.Equal(t, 2, len(h.Healthy()))
+}
+
+func TestUsesAllEndpointsWhenNoneAreHealthy(t *testing.T) {
+	h, b1, b2 := setupHealthCheck(t, HealthCheckConfig{UnhealthyThreshold: 1})
+	b1.setHealthy(false)
+	b2.setHealthy(false)
+
+	h.Check()
+
+	assert.Equal(t, []url.URL{b1.endpoint(), b2.endpoint()}, h.Healthy())
+}
+
+func TestEjectsOutlierAfterConsecutiveFailures(t *testing.T) {
+	h, b1, b2 := setupHealthCheck(t, HealthCheckConfig{ConsecutiveFailures: 3, EjectionTime: 50 * time.Millisecond})
+	lb := NewLoadBalancer(h, []url.URL{b1.endpoint(), b2.endpoint()})
+
+	lb.Report(b1.endpoint(), errors.New("connection refused"))
+	lb.Report(b1.endpoint(), errors.New("connection refused"))
+	lb.Report(b1.endpoint(), nil)
+	lb.Report(b1.endpoint(), errors.New("connection refused"))
+	lb.Report(b1.endpoint(), errors.New("connection refused"))
+	assert.Equal(t, 2, len(h.Healthy()), "a success should reset the failure count")
+
+	lb.Report(b1.endpoint(), errors.New("connection refused"))
+	assert.Equal(t, []url.URL{b2.endpoint()}, h.Healthy())
+	assert.Equal(t, b2.endpoint(), lb.GetEndpoint())
+
+	time.Sleep(60 * time.Millisecond)
+
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		seen[lb.GetEndpoint().Host] = true
+	}
+	assert.True(t, seen[b1.endpoint().Host], "outlier should be restored after the ejection time")
+}
+
+func TestKeepsHealthOfKnownEndpointsWhenEndpointsChange(t *testing.T) {
+	h, b1, b2 := setupHealthCheck(t, HealthCheckConfig{UnhealthyThreshold: 1})
+	b1.setHealthy(false)
+	h.Check()
+
+	b3 := newTestBackend(t)
+	h.SetEndpoints([]url.URL{b1.endpoint(), b2.endpoint(), b3.endpoint()})
+
+	assert.Equal(t, []url.URL{b2.endpoint(), b3.endpoint()}, h.Healthy())
+}
+
+func TestStartProbesPeriodicallyUntilStopped(t *testing.T) {
+	h, b1, _ := setupHealthCheck(t, HealthCheckConfig{Interval: 10 * time.Millisecond})
+
+	h.Start()
+	time.Sleep(55 * time.Millisecond)
+	h.Stop()
+
+	probes := atomic.LoadInt32(&b1.probes)
+	assert.True(t, probes >= 3, "expected at least 3 probes got %v", probes)
+
+	time.Sleep(30 * time.Millisecond)
+	assert.Equal(t, probes, atomic.LoadInt32(&b1.probes))
+}
@@ -0,0 +1,325 @@
+package loadbalancing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+)
+
+const (
+	// DefaultHedgePercentile is the latency percentile HedgedTransport waits
+	// for before sending a backup request when Percentile is not set.
+	DefaultHedgePercentile = 0.95
+	// DefaultHedgeRatio is the fraction of requests HedgedTransport may back
+	// up when MaxRatio is not set.
+	DefaultHedgeRatio = 0.05
+	// DefaultHedgeWindow is the number of recent latencies HedgedTransport
+	// keeps when Window is not set.
+	DefaultHedgeWindow = 200
+
+	// hedgeMinSamples is the number of latencies needed before the
+	// percentile means anything, no backups are sent before that
+	hedgeMinSamples = 10
+	// hedgeBurst is the most backups which can be saved up
+	hedgeBurst = 10
+)
+
+// HedgeStats describe the backup requests sent by a HedgedTransport
+type HedgeStats struct {
+	Requests   uint64
+	Hedged     uint64
+	BackupWins uint64
+	// Delay is the current wait before a backup request, 0 while there are
+	// not enough latencies to know it.
+	Delay time.Duration
+}
+
+// HedgedTransport is an http.RoundTripper which cuts tail latency by sending
+// a backup request to a second endpoint when the first has not responded
+// within a percentile of recent latencies. The first response wins and the
+// other request is canceled.
+//
+// Backups add load at the moment a service is slow, so they are limited to
+// MaxRatio of all requests. Only idempotent requests are hedged.
+type HedgedTransport struct {
+	// LoadBalancer chooses the endpoints.
+	LoadBalancer *LoadBalancer
+	// Base sends the rewritten requests, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// Key returns the key used to choose the first endpoint, see Transport.
+	Key func(r *http.Request) string
+	// Percentile of recent latencies after which a backup request is sent,
+	// defaults to DefaultHedgePercentile.
+	Percentile float64
+	// MinDelay is the shortest wait before a backup request.
+	MinDelay time.Duration
+	// MaxRatio is the largest fraction of requests which are backed up,
+	// defaults to DefaultHedgeRatio.
+	MaxRatio float64
+	// Window is the number of recent latencies the percentile is taken
+	// from, defaults to DefaultHedgeWindow.
+	Window int
+
+	mutex      sync.Mutex
+	latencies  []time.Duration
+	next       int
+	tokens     float64
+	requests   uint64
+	hedged     uint64
+	backupWins uint64
+}
+
+type hedgeResult struct {
+	attempt  int
+	endpoint url.URL
+	resp     *http.Response
+	err      error
+	duration time.Duration
+	backup   bool
+	cancel   context.CancelFunc
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *HedgedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	tried := map[string]bool{}
+	first, ok := pick(t.LoadBalancer, t.Key, req, tried)
+	if !ok {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, ErrNoEndpoints
+	}
+	tried[first.Host] = true
+	t.deposit()
+
+	if !retry.Idempotent(req) {
+		r, err := rewrite(req, first, false)
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := t.base().RoundTrip(r)
+		t.report(hedgeResult{endpoint: first, resp: resp, err: err, duration: time.Since(start)}, req)
+
+		return resp, err
+	}
+
+	results := make(chan hedgeResult, 2)
+	cancels := []context.CancelFunc{}
+	launch := func(endpoint url.URL, backup bool) {
+		ctx, cancel := context.WithCancel(req.Context())
+		attempt := len(cancels)
+		cancels = append(cancels, cancel)
+
+		r, err := rewrite(req.WithContext(ctx), endpoint, backup)
+		if err != nil {
+			results <- hedgeResult{attempt: attempt, endpoint: endpoint, err: err, backup: backup, cancel: cancel}
+			return
+		}
+
+		go func() {
+			start := time.Now()
+			resp, err := t.base().RoundTrip(r)
+			results <- hedgeResult{attempt: attempt, endpoint: endpoint, resp: resp, err: err, duration: time.Since(start), backup: backup, cancel: cancel}
+		}()
+	}
+
+	launch(first, false)
+	outstanding := 1
+
+	var hedge <-chan time.Time
+	if delay, ok := t.delay(); ok {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		hedge = timer.C
+	}
+
+	var lastErr error
+	for outstanding > 0 {
+		select {
+		case <-hedge:
+			hedge = nil
+			if second, ok := pick(t.LoadBalancer, nil, req, tried); ok && t.withdraw() {
+				tried[second.Host] = true
+				launch(second, true)
+				outstanding++
+			}
+		case res := <-results:
+			outstanding--
+			t.report(res, req)
+
+			if res.err != nil {
+				res.cancel()
+				lastErr = res.err
+				continue
+			}
+
+			// cancel the loser, its response is thrown away when it arrives
+			for i, cancel := range cancels {
+				if i != res.attempt {
+					cancel()
+				}
+			}
+			go discard(results, outstanding)
+
+			if res.backup {
+				t.mutex.Lock()
+				t.backupWins++
+				t.mutex.Unlock()
+			}
+			res.resp.Body = &cancelBody{ReadCloser: res.resp.Body, cancel: res.cancel}
+
+			return res.resp, nil
+		}
+	}
+
+	return nil, lastErr
+}
+
+// Stats returns the number of requests and backups sent so far
+func (t *HedgedTransport) Stats() HedgeStats {
+	delay, _ := t.delay()
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return HedgeStats{
+		Requests:   t.requests,
+		Hedged:     t.hedged,
+		BackupWins: t.backupWins,
+		Delay:      delay,
+	}
+}
+
+func (t *HedgedTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// report passes the outcome of an attempt to the LoadBalancer and records
+// the latency of responses. Attempts canceled because the other one won are
+// not reported.
+func (t *HedgedTransport) report(res hedgeResult, req *http.Request) {
+	switch {
+	case res.err != nil:
+		if req.Context().Err() == nil {
+			t.LoadBalancer.Report(res.endpoint, res.err)
+		}
+	case res.resp.StatusCode >= http.StatusInternalServerError:
+		t.LoadBalancer.Report(res.endpoint, fmt.Errorf("loadbalancing: %v returned %v", res.endpoint.Host, res.resp.Status))
+	default:
+		t.LoadBalancer.Report(res.endpoint, nil)
+		t.record(res.duration)
+	}
+}
+
+func (t *HedgedTransport) record(d time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	window := t.Window
+	if window <= 0 {
+		window = DefaultHedgeWindow
+	}
+
+	if len(t.latencies) < window {
+		t.latencies = append(t.latencies, d)
+		return
+	}
+
+	t.latencies[t.next%len(t.latencies)] = d
+	t.next++
+}
+
+// delay returns the wait before a backup request, false when there are not
+// enough latencies yet
+func (t *HedgedTransport) delay() (time.Duration, bool) {
+	t.mutex.Lock()
+	sorted := append([]time.Duration(nil), t.latencies...)
+	t.mutex.Unlock()
+
+	if len(sorted) < hedgeMinSamples {
+		return 0, false
+	}
+
+	percentile := t.Percentile
+	if percentile <= 0 || percentile > 1 {
+		percentile = DefaultHedgePercentile
+	}
+
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	d := sorted[int(percentile*float64(len(sorted)-1))]
+	if d < t.MinDelay {
+		d = t.MinDelay
+	}
+
+	return d, true
+}
+
+// deposit counts a request and earns it MaxRatio of a backup
+func (t *HedgedTransport) deposit() {
+	ratio := t.MaxRatio
+	if ratio <= 0 {
+		ratio = DefaultHedgeRatio
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.requests++
+	t.tokens += ratio
+	if t.tokens > hedgeBurst {
+		t.tokens = hedgeBurst
+	}
+}
+
+// withdraw spends a backup, false when the backups are used up
+func (t *HedgedTransport) withdraw() bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.tokens < 1 {
+		return false
+	}
+
+	t.tokens--
+	t.hedged++
+
+	return true
+}
+
+// discard closes the responses of the attempts which lost
+func discard(results <-chan hedgeResult, n int) {
+	for i := 0; i < n; i++ {
+		res := <-results
+		res.cancel()
+		if res.resp != nil {
+			res.resp.Body.Close()
+		}
+	}
+}
+
+// cancelBody cancels the context of the winning attempt once its body has
+// been read
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+
+	return err
+}
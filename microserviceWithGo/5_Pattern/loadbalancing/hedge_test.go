@@ -0,0 +1,131 @@
+package loadbalancing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newSlowServer returns a server which waits for its delay before answering
+// with its name, canceled requests are counted
+func newSlowServer(t *testing.T, name string, delay *int64, canceled *int32) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Duration(atomic.LoadInt64(delay))):
+			fmt.Fprint(rw, name)
+		case <-r.Context().Done():
+			atomic.AddInt32(canceled, 1)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func setupHedging(t *testing.T) (client *http.Client, hedged *HedgedTransport, slowDelay *int64, canceled *int32) {
+	slowDelay, fastDelay := new(int64), new(int64)
+	canceled = new(int32)
+	slow := newSlowServer(t, "slow", slowDelay, canceled)
+	fast := newSlowServer(t, "fast", fastDelay, canceled)
+
+	lb := NewLoadBalancer(&orderedStrategy{}, []url.URL{serverEndpoint(slow), serverEndpoint(fast)})
+	hedged = &HedgedTransport{LoadBalancer: lb, MaxRatio: 1}
+	client = &http.Client{Transport: hedged}
+
+	// learn the latencies while both endpoints are fast, this leaves the
+	// slow endpoint next in line
+	for i := 0; i < hedgeMinSamples; i++ {
+		resp, err := client.Get("http://kittens/")
+		assert.Nil(t, err)
+		readBody(t, resp)
+	}
+
+	return client, hedged, slowDelay, canceled
+}
+
+func TestSendsBackupRequestToSecondEndpoint(t *testing.T) {
+	client, hedged, slowDelay, canceled := setupHedging(t)
+	atomic.StoreInt64(slowDelay, int64(time.Second))
+
+	start := time.Now()
+	resp, err := client.Get("http://kittens/")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "fast", readBody(t, resp))
+	assert.True(t, time.Since(start) < time.Second)
+
+	stats := hedged.Stats()
+	assert.Equal(t, uint64(1), stats.Hedged)
+	assert.Equal(t, uint64(1), stats.BackupWins)
+	assert.True(t, stats.Delay > 0)
+
+	// the slow request is canceled
+	for i := 0; i < 100 && atomic.LoadInt32(canceled) == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(canceled))
+}
+
+func TestDoesNotHedgeNonIdempotentRequest(t *testing.T) {
+	client, hedged, slowDelay, _ := setupHedging(t)
+	atomic.StoreInt64(slowDelay, int64(50*time.Millisecond))
+
+	resp, err := client.Post("http://kittens/", "text/plain", strings.NewReader("Garfield"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, "slow", readBody(t, resp))
+	assert.Equal(t, uint64(0), hedged.Stats().Hedged)
+}
+
+func TestDoesNotHedgeWithoutLatencies(t *testing.T) {
+	canceled := new(int32)
+	slow := newSlowServer(t, "slow", new(int64), canceled)
+	lb := NewLoadBalancer(&orderedStrategy{}, []url.URL{serverEndpoint(slow)})
+	hedged := &HedgedTransport{LoadBalancer: lb, MaxRatio: 1}
+
+	resp, err := (&http.Client{Transport: hedged}).Get("http://kittens/")
+
+	assert.Nil(t, err)
+	readBody(t, resp)
+	assert.Equal(t, HedgeStats{Requests: 1}, hedged.Stats())
+}
+
+func TestLimitsBackupsToRatio(t *testing.T) {
+	hedged := &HedgedTransport{MaxRatio: 0.25}
+
+	backups := 0
+	for i := 0; i < 8; i++ {
+		hedged.deposit()
+	}
+	for hedged.withdraw() {
+		backups++
+	}
+
+	assert.Equal(t, 2, backups)
+}
+
+func TestHedgeDelayIsPercentileOfLatencies(t *testing.T) {
+	hedged := &HedgedTransport{Percentile: 0.9, MinDelay: 5 * time.Millisecond}
+	for i := 1; i <= 100; i++ {
+		hedged.record(time.Duration(i) * time.Millisecond)
+	}
+
+	delay, ok := hedged.delay()
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Millisecond, delay)
+
+	hedged = &HedgedTransport{Window: 10, MinDelay: 5 * time.Millisecond}
+	for i := 0; i < 20; i++ {
+		hedged.record(time.Millisecond)
+	}
+	delay, _ = hedged.delay()
+	assert.Equal(t, 5*time.Millisecond, delay)
+	assert.Equal(t, 10, len(hedged.latencies))
+}
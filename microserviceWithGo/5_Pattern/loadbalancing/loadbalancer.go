@@ -0,0 +1,98 @@
+package loadbalancing
+
+import (
+	"math/rand"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Strategy is an interface to be implemented by loadbalancing
+// strategies like round robin or random.
+type Strategy interface {
+	NextEndpoint() url.URL
+	SetEndpoints([]url.URL)
+}
+
+// KeyedStrategy is implemented by strategies which select an endpoint
+// based on a request key, the same key is always routed to the same endpoint
+// while the set of endpoints does not change.
+type KeyedStrategy interface {
+	Strategy
+	EndpointForKey(key string) url.URL
+}
+
+// Reporter is implemented by strategies which use the outcome of calls to
+// the endpoints they returned, for example to eject failing endpoints.
+type Reporter interface {
+	Report(endpoint url.URL, err error)
+}
+
+// RandomStrategy implements Strategy for random endopoint selection
+type RandomStrategy struct {
+	mutex     sync.RWMutex
+	endpoints []url.URL
+}
+
+// NextEndpoint returns an endpoint using a random strategy, an empty URL is
+// returned when there are no endpoints
+func (r *RandomStrategy) NextEndpoint() url.URL {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if len(r.endpoints) == 0 {
+		return url.URL{}
+	}
+
+	s1 := rand.NewSource(time.Now().UnixNano())
+	r1 := rand.New(s1)
+
+	return r.endpoints[r1.Intn(len(r.endpoints))]
+}
+
+// SetEndpoints sets the available endpoints for use by the strategy
+func (r *RandomStrategy) SetEndpoints(endpoints []url.URL) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.endpoints = endpoints
+}
+
+// LoadBalancer is returns endpoints for downstream calls
+type LoadBalancer struct {
+	strategy Strategy
+}
+
+// NewLoadBalancer creates a new loadbalancer and setting the given strategy
+func NewLoadBalancer(strategy Strategy, endpoints []url.URL) *LoadBalancer {
+	strategy.SetEndpoints(endpoints)
+	return &LoadBalancer{strategy: strategy}
+}
+
+// GetEndpoint gets an endpoint based on the given strategy
+func (l *LoadBalancer) GetEndpoint() url.URL {
+	return l.strategy.NextEndpoint()
+}
+
+// GetEndpointForKey gets the endpoint for the given request key, if the
+// strategy is not a KeyedStrategy the key is ignored and GetEndpoint is used
+func (l *LoadBalancer) GetEndpointForKey(key string) url.URL {
+	if ks, ok := l.strategy.(KeyedStrategy); ok {
+		return ks.EndpointForKey(key)
+	}
+
+	return l.strategy.NextEndpoint()
+}
+
+// Report passes the outcome of a call to the given endpoint to the strategy,
+// a nil error is a success. It does nothing if the strategy is not a Reporter
+func (l *LoadBalancer) Report(endpoint url.URL, err error) {
+	if r, ok := l.strategy.(Reporter); ok {
+		r.Report(endpoint, err)
+	}
+}
+
+// UpdateEndpoints updates the endpoints available to the strategy
+func (l *LoadBalancer) UpdateEndpoints(urls []url.URL) {
+	l.strategy.SetEndpoints(urls)
+}
@@ -0,0 +1,155 @@
+package loadbalancing
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+)
+
+// ErrNoEndpoints is returned by Transport when the LoadBalancer has no
+// endpoint to send the request to.
+var ErrNoEndpoints = errors.New("loadbalancing: no endpoints available")
+
+// DefaultMaxAttempts is the number of endpoints Transport tries for an
+// idempotent request when MaxAttempts is not set.
+const DefaultMaxAttempts = 3
+
+// Transport is an http.RoundTripper which sends every request to an endpoint
+// chosen by a LoadBalancer, the scheme and host of the request URL are
+// replaced with the ones of the endpoint.
+//
+// Idempotent requests which fail to reach an endpoint are retried on a
+// different endpoint. The outcome of every attempt is passed back to the
+// LoadBalancer with Report, responses with a 5xx status count as failures.
+type Transport struct {
+	// LoadBalancer chooses the endpoint for every request.
+	LoadBalancer *LoadBalancer
+	// Base sends the rewritten requests, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// MaxAttempts is the maximum number of endpoints tried for an idempotent
+	// request, defaults to DefaultMaxAttempts.
+	MaxAttempts int
+	// Key returns the key used to choose the endpoint, for example a user id
+	// with a ConsistentHashStrategy. When nil GetEndpoint is used.
+	Key func(r *http.Request) string
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempts := t.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+	if !retry.Idempotent(req) {
+		attempts = 1
+	}
+
+	tried := map[string]bool{}
+	var lastErr error
+
+	for i := 0; i < attempts; i++ {
+		endpoint, ok := pick(t.LoadBalancer, t.Key, req, tried)
+		if !ok {
+			break
+		}
+		tried[endpoint.Host] = true
+
+		r, err := rewrite(req, endpoint, i > 0)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base().RoundTrip(r)
+		if err != nil {
+			t.LoadBalancer.Report(endpoint, err)
+			lastErr = err
+
+			if req.Context().Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if resp.StatusCode >= http.StatusInternalServerError {
+			t.LoadBalancer.Report(endpoint, fmt.Errorf("loadbalancing: %v returned %v", endpoint.Host, resp.Status))
+		} else {
+			t.LoadBalancer.Report(endpoint, nil)
+		}
+
+		return resp, nil
+	}
+
+	if lastErr == nil {
+		lastErr = ErrNoEndpoints
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	return nil, lastErr
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+
+	return http.DefaultTransport
+}
+
+// pick returns an endpoint which has not been tried yet. A keyed strategy
+// always returns the same endpoint for a key so retries fall back to
+// GetEndpoint.
+func pick(lb *LoadBalancer, key func(r *http.Request) string, req *http.Request, tried map[string]bool) (url.URL, bool) {
+	if key != nil && len(tried) == 0 {
+		e := lb.GetEndpointForKey(key(req))
+		return e, e.Host != ""
+	}
+
+	// the strategy decides the order so give it a few chances to return an
+	// endpoint we have not used
+	for i := 0; i < 10; i++ {
+		e := lb.GetEndpoint()
+		if e.Host == "" {
+			return e, false
+		}
+		if !tried[e.Host] {
+			return e, true
+		}
+	}
+
+	return url.URL{}, false
+}
+
+func rewrite(req *http.Request, endpoint url.URL, retry bool) (*http.Request, error) {
+	r := req.Clone(req.Context())
+
+	if endpoint.Scheme != "" {
+		r.URL.Scheme = endpoint.Scheme
+	} else if r.URL.Scheme == "" {
+		r.URL.Scheme = "http"
+	}
+	// only keep the Host header when it was set explicitly by the caller
+	if req.Host == req.URL.Host {
+		r.Host = ""
+	}
+	r.URL.Host = endpoint.Host
+	if endpoint.Path != "" {
+		r.URL.Path = strings.TrimSuffix(endpoint.Path, "/") + "/" + strings.TrimPrefix(req.URL.Path, "/")
+		r.URL.RawPath = ""
+	}
+
+	if retry && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+
+	return r, nil
+}
@@ -0,0 +1,187 @@
+package loadbalancing
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// orderedStrategy returns the endpoints in order and records every reported
+// outcome
+type orderedStrategy struct {
+	mutex     sync.Mutex
+	endpoints []url.URL
+	next      int
+	reports   map[string][]error
+}
+
+func (o *orderedStrategy) NextEndpoint() url.URL {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if len(o.endpoints) == 0 {
+		return url.URL{}
+	}
+
+	e := o.endpoints[o.next%len(o.endpoints)]
+	o.next++
+	return e
+}
+
+func (o *orderedStrategy) SetEndpoints(endpoints []url.URL) {
+	o.endpoints = endpoints
+	o.reports = map[string][]error{}
+}
+
+func (o *orderedStrategy) Report(endpoint url.URL, err error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	o.reports[endpoint.Host] = append(o.reports[endpoint.Host], err)
+}
+
+func newEchoServer(t *testing.T, name string, status int) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		rw.WriteHeader(status)
+		rw.Write([]byte(name + " " + r.Method + " " + r.Host + r.URL.Path + " " + string(body)))
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// newDeadEndpoint returns an endpoint which refuses connections
+func newDeadEndpoint() url.URL {
+	s := httptest.NewServer(http.NotFoundHandler())
+	s.Close()
+
+	u, _ := url.Parse(s.URL)
+	return *u
+}
+
+func serverEndpoint(s *httptest.Server) url.URL {
+	u, _ := url.Parse(s.URL)
+	return *u
+}
+
+func setupTransport(endpoints ...url.URL) (*http.Client, *orderedStrategy) {
+	strategy := &orderedStrategy{}
+	lb := NewLoadBalancer(strategy, endpoints)
+
+	return &http.Client{Transport: &Transport{LoadBalancer: lb}}, strategy
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	assert.Nil(t, err)
+
+	return string(body)
+}
+
+func TestSendsRequestToEndpointFromLoadBalancer(t *testing.T) {
+	s := newEchoServer(t, "one", http.StatusOK)
+	client, strategy := setupTransport(serverEndpoint(s))
+
+	resp, err := client.Get("http://kittens/search")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "one GET "+serverEndpoint(s).Host+"/search ", readBody(t, resp))
+	assert.Equal(t, []error{nil}, strategy.reports[serverEndpoint(s).Host])
+}
+
+func TestRetriesIdempotentRequestOnDifferentEndpoint(t *testing.T) {
+	dead := newDeadEndpoint()
+	s := newEchoServer(t, "two", http.StatusOK)
+	client, strategy := setupTransport(dead, serverEndpoint(s))
+
+	req, _ := http.NewRequest(http.MethodPut, "http://kittens/kittens/1", strings.NewReader("Garfield"))
+	resp, err := client.Do(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "two PUT "+serverEndpoint(s).Host+"/kittens/1 Garfield", readBody(t, resp))
+	assert.Equal(t, 1, len(strategy.reports[dead.Host]))
+	assert.NotNil(t, strategy.reports[dead.Host][0])
+	assert.Equal(t, []error{nil}, strategy.reports[serverEndpoint(s).Host])
+}
+
+func TestDoesNotRetryNonIdempotentRequest(t *testing.T) {
+	dead := newDeadEndpoint()
+	s := newEchoServer(t, "two", http.StatusOK)
+	client, strategy := setupTransport(dead, serverEndpoint(s))
+
+	_, err := client.Post("http://kittens/kittens", "text/plain", strings.NewReader("Garfield"))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, len(strategy.reports[serverEndpoint(s).Host]))
+}
+
+func TestRetriesNonIdempotentRequestWithIdempotencyKey(t *testing.T) {
+	dead := newDeadEndpoint()
+	s := newEchoServer(t, "two", http.StatusOK)
+	client, _ := setupTransport(dead, serverEndpoint(s))
+
+	req, _ := http.NewRequest(http.MethodPost, "http://kittens/kittens", strings.NewReader("Garfield"))
+	req.Header.Set("Idempotency-Key", "abc")
+	resp, err := client.Do(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "two POST "+serverEndpoint(s).Host+"/kittens Garfield", readBody(t, resp))
+}
+
+func TestReturnsErrorWhenAllEndpointsFail(t *testing.T) {
+	client, strategy := setupTransport(newDeadEndpoint(), newDeadEndpoint())
+
+	_, err := client.Get("http://kittens/search")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(strategy.reports))
+}
+
+func TestReturnsErrNoEndpointsWhenLoadBalancerIsEmpty(t *testing.T) {
+	transport := &Transport{LoadBalancer: NewLoadBalancer(&RandomStrategy{}, nil)}
+	req := httptest.NewRequest(http.MethodGet, "http://kittens/search", nil)
+
+	_, err := transport.RoundTrip(req)
+
+	assert.Equal(t, ErrNoEndpoints, err)
+}
+
+func TestReportsServerErrorsAsFailuresWithoutRetrying(t *testing.T) {
+	s := newEchoServer(t, "one", http.StatusInternalServerError)
+	s2 := newEchoServer(t, "two", http.StatusOK)
+	client, strategy := setupTransport(serverEndpoint(s), serverEndpoint(s2))
+
+	resp, err := client.Get("http://kittens/search")
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, 1, len(strategy.reports[serverEndpoint(s).Host]))
+	assert.NotNil(t, strategy.reports[serverEndpoint(s).Host][0])
+	assert.Equal(t, 0, len(strategy.reports[serverEndpoint(s2).Host]))
+}
+
+func TestUsesKeyToChooseEndpoint(t *testing.T) {
+	s1 := newEchoServer(t, "one", http.StatusOK)
+	s2 := newEchoServer(t, "two", http.StatusOK)
+	lb := NewLoadBalancer(&ConsistentHashStrategy{}, []url.URL{serverEndpoint(s1), serverEndpoint(s2)})
+	client := &http.Client{Transport: &Transport{
+		LoadBalancer: lb,
+		Key:          func(r *http.Request) string { return r.URL.Query().Get("user") },
+	}}
+
+	expected := lb.GetEndpointForKey("nic")
+	for i := 0; i < 5; i++ {
+		resp, err := client.Get("http://kittens/search?user=nic")
+		assert.Nil(t, err)
+		assert.Contains(t, readBody(t, resp), expected.Host)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/bulkhead"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/resilience"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+)
+
+func main() {
+	// 전체 호출은 2초 안에 끝나야 하고, 실패한 시도는 최대 3회까지 재시도
+	// 모든 시도는 차단기를 거치고, 차단기를 통과한 시도만 벌크헤드 슬롯을 사용
+	p := resilience.Pipeline(
+		resilience.Timeout(2*time.Second),
+		resilience.Retry(retry.Policy{MaxAttempts: 3}),
+		resilience.Breaker(circuit.New("example", circuit.Settings{
+			Trip: circuit.ConsecutiveFailures(5),
+			OnStateChange: func(name string, from, to circuit.State) {
+				fmt.Printf("Breaker %v: %v -> %v\n", name, from, to)
+			},
+		})),
+		resilience.Bulkhead(bulkhead.New("example", bulkhead.Config{MaxConcurrent: 2})),
+	)
+
+	for i := 0; i < 10; i++ {
+		err := p.Execute(context.Background(), func(ctx context.Context) error {
+			// Call some service
+			if rand.Intn(2) == 0 {
+				return fmt.Errorf("Unavailable")
+			}
+			return nil
+		})
+
+		fmt.Println("Result:", err)
+	}
+}
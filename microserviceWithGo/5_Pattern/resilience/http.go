@@ -0,0 +1,323 @@
+package resilience
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/bulkhead"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+)
+
+// DefaultMaxBodySize is the default value of Chain.MaxBodySize
+const DefaultMaxBodySize = 10 << 20
+
+// ErrBodyTooLarge is returned by RoundTripper when the response body is
+// larger than Chain.MaxBodySize
+var ErrBodyTooLarge = errors.New("resilience: body exceeds MaxBodySize")
+
+// StatusError is passed through the pipeline for responses with a 5xx
+// status code so that they count as failures, it is never returned by the
+// http wrappers.
+type StatusError struct {
+	StatusCode int
+}
+
+func (s *StatusError) Error() string {
+	return fmt.Sprintf("resilience: server returned %v %v", s.StatusCode, http.StatusText(s.StatusCode))
+}
+
+// Handler is middleware which runs the next handler through the pipeline.
+// The response of every attempt is buffered and only the final one is
+// written, when the pipeline rejects the request or times out an error
+// status is returned instead: 503 for an open breaker or a full bulkhead and
+// 504 for a timeout. The request body is buffered so that every attempt can
+// read it, only idempotent requests are retried, see retry.Idempotent.
+// Bodies larger than MaxBodySize are rejected with 413.
+func (c *Chain) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := bufferBody(rw, r, c.maxBodySize()); err != nil {
+			status := http.StatusBadRequest
+			if err == ErrBodyTooLarge {
+				status = http.StatusRequestEntityTooLarge
+			}
+			http.Error(rw, http.StatusText(status), status)
+			return
+		}
+		idempotent := retry.Idempotent(r)
+
+		var (
+			mutex sync.Mutex
+			last  *responseBuffer
+		)
+
+		err := c.Execute(r.Context(), func(ctx context.Context) error {
+			buf := &responseBuffer{header: make(http.Header)}
+			mutex.Lock()
+			if last != nil {
+				last.close()
+			}
+			last = buf
+			mutex.Unlock()
+
+			req := r.WithContext(ctx)
+			if r.GetBody != nil {
+				body, err := r.GetBody()
+				if err != nil {
+					return retry.Permanent(err)
+				}
+				req.Body = body
+			}
+
+			next.ServeHTTP(buf, req)
+
+			if status := buf.statusCode(); status >= http.StatusInternalServerError {
+				if !idempotent {
+					return retry.Permanent(&StatusError{StatusCode: status})
+				}
+				return &StatusError{StatusCode: status}
+			}
+			return nil
+		})
+
+		mutex.Lock()
+		buf := last
+		mutex.Unlock()
+
+		var statusErr *StatusError
+		if buf != nil && (err == nil || errors.As(err, &statusErr)) {
+			buf.writeTo(rw)
+			return
+		}
+		if buf != nil {
+			buf.close()
+		}
+
+		status := statusCode(err)
+		http.Error(rw, http.StatusText(status), status)
+	})
+}
+
+// bufferBody reads the request body into memory and sets GetBody so that it
+// can be read again by every attempt, it fails with ErrBodyTooLarge when the
+// body is longer than max
+func bufferBody(rw http.ResponseWriter, r *http.Request, max int64) error {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(rw, r.Body, max))
+	r.Body.Close()
+	if err != nil {
+		// MaxBytesReader returns exactly max bytes before failing
+		if int64(len(body)) >= max {
+			return ErrBodyTooLarge
+		}
+		return err
+	}
+
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	return nil
+}
+
+// statusCode returns the status code sent to the client when the pipeline
+// fails without a response
+func statusCode(err error) int {
+	switch {
+	case errors.Is(err, circuit.ErrOpen), errors.Is(err, bulkhead.ErrFull), errors.Is(err, bulkhead.ErrTimeout):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// responseBuffer records the response of one attempt, writes from handlers
+// which are still running once the attempt has been given up are discarded
+type responseBuffer struct {
+	mutex  sync.Mutex
+	header http.Header
+	body   bytes.Buffer
+	status int
+	closed bool
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed {
+		return 0, http.ErrHandlerTimeout
+	}
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+
+	return b.body.Write(p)
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.closed || b.status != 0 {
+		return
+	}
+
+	b.status = status
+}
+
+func (b *responseBuffer) statusCode() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.status == 0 {
+		return http.StatusOK
+	}
+	return b.status
+}
+
+func (b *responseBuffer) close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+}
+
+func (b *responseBuffer) writeTo(rw http.ResponseWriter) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.closed = true
+	for k, v := range b.header {
+		rw.Header()[k] = v
+	}
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	rw.WriteHeader(b.status)
+	rw.Write(b.body.Bytes())
+}
+
+// RoundTripper returns an http.RoundTripper which sends every request
+// through the pipeline using base, which defaults to http.DefaultTransport.
+// Responses with a 5xx status code count as failures and the last response
+// is returned when no attempt succeeds. Only idempotent requests are
+// retried, see retry.Transport. Response bodies are read within the
+// pipeline so that a Timeout covers the whole exchange, bodies larger than
+// MaxBodySize fail with ErrBodyTooLarge and are not retried.
+func (c *Chain) RoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &roundTripper{chain: c, base: base}
+}
+
+type roundTripper struct {
+	chain *Chain
+	base  http.RoundTripper
+}
+
+func (t *roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := retry.Idempotent(req)
+
+	var (
+		mutex   sync.Mutex
+		attempt int
+		resp    *http.Response
+		done    bool
+	)
+	err := t.chain.Execute(req.Context(), func(ctx context.Context) error {
+		mutex.Lock()
+		first := attempt == 0
+		attempt++
+		mutex.Unlock()
+
+		r := req.Clone(ctx)
+		if !first && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return retry.Permanent(err)
+			}
+			r.Body = body
+		}
+
+		res, err := t.base.RoundTrip(r)
+		if err == nil {
+			err = buffer(res, t.chain.maxBodySize())
+		}
+		if err != nil {
+			if !idempotent {
+				return retry.Permanent(err)
+			}
+			return err
+		}
+
+		// attempts still running after a timeout must not replace the response
+		mutex.Lock()
+		if !done {
+			resp = res
+		}
+		mutex.Unlock()
+
+		if res.StatusCode >= http.StatusInternalServerError {
+			if !idempotent {
+				return retry.Permanent(&StatusError{StatusCode: res.StatusCode})
+			}
+			return &StatusError{StatusCode: res.StatusCode}
+		}
+
+		return nil
+	})
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	done = true
+
+	var statusErr *StatusError
+	if resp != nil && (err == nil || errors.As(err, &statusErr)) {
+		return resp, nil
+	}
+	if attempt == 0 && req.Body != nil {
+		req.Body.Close()
+	}
+
+	return nil, err
+}
+
+// buffer reads the body of the response into memory, at most max bytes
+func buffer(resp *http.Response, max int64) error {
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, max+1))
+	resp.Body.Close()
+	if err != nil {
+		return err
+	}
+	if int64(len(body)) > max {
+		return retry.Permanent(ErrBodyTooLarge)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return nil
+}
+
+func (c *Chain) maxBodySize() int64 {
+	if c.MaxBodySize <= 0 {
+		return DefaultMaxBodySize
+	}
+	return c.MaxBodySize
+}
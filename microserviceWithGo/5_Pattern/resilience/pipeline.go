@@ -0,0 +1,98 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/bulkhead"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/timeout"
+)
+
+// Policy is an interface to be implemented by resilience patterns, Execute
+// calls fn zero or more times and returns the outcome.
+type Policy interface {
+	Execute(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// PolicyFunc allows the use of an ordinary function as a Policy
+type PolicyFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+// Execute calls f(ctx, fn)
+func (f PolicyFunc) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	return f(ctx, fn)
+}
+
+// Chain is a Policy made of other policies, see Pipeline
+type Chain struct {
+	// MaxBodySize limits the request bodies buffered by Handler and the
+	// response bodies buffered by RoundTripper, defaults to
+	// DefaultMaxBodySize
+	MaxBodySize int64
+
+	policies []Policy
+}
+
+// Pipeline composes the given policies into one. The first policy is the
+// outermost and every policy wraps the ones after it, so
+//
+//	Pipeline(Timeout(time.Second), Retry(p), Breaker(b), Bulkhead(bh))
+//
+// gives all attempts together one second, retries every failed attempt,
+// sends every attempt through the breaker and only takes a bulkhead slot for
+// attempts the breaker lets through. This is the recommended order: a
+// timeout inside Retry would limit each attempt instead of the whole call,
+// and a breaker outside Retry would only see the outcome of the last attempt.
+func Pipeline(policies ...Policy) *Chain {
+	return &Chain{policies: policies}
+}
+
+// Execute runs fn through all policies of the pipeline
+func (c *Chain) Execute(ctx context.Context, fn func(ctx context.Context) error) error {
+	call := fn
+	for i := len(c.policies) - 1; i >= 0; i-- {
+		p, next := c.policies[i], call
+		call = func(ctx context.Context) error {
+			return p.Execute(ctx, next)
+		}
+	}
+
+	return call(ctx)
+}
+
+// Timeout cancels the context of the call after d, see timeout.Runner
+func Timeout(d time.Duration) Policy {
+	runner := timeout.New(d)
+
+	return PolicyFunc(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return runner.Run(ctx, fn)
+	})
+}
+
+// Retry retries failed calls with the given retry.Policy. Calls rejected by
+// an open breaker further down the pipeline are not retried.
+func Retry(p retry.Policy) Policy {
+	classifier := p.Classifier
+	if classifier == nil {
+		classifier = retry.DefaultClassifier
+	}
+	p.Classifier = func(err error) bool {
+		return !errors.Is(err, circuit.ErrOpen) && classifier(err)
+	}
+
+	return PolicyFunc(p.Do)
+}
+
+// Breaker sends the calls through the given circuit breaker
+func Breaker(b *circuit.Breaker) Policy {
+	return PolicyFunc(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		return b.Run(func() error { return fn(ctx) })
+	})
+}
+
+// Bulkhead runs the calls in the given bulkhead
+func Bulkhead(b *bulkhead.Bulkhead) Policy {
+	return PolicyFunc(b.Execute)
+}
@@ -0,0 +1,264 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/bulkhead"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/circuit"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailed = errors.New("failed")
+
+// noWait retries straight away
+var noWait = retry.Policy{MaxAttempts: 3, Backoff: retry.ConstantBackoff(0)}
+
+// trace is a policy which records when it is entered and left
+func trace(name string, calls *[]string) Policy {
+	return PolicyFunc(func(ctx context.Context, fn func(ctx context.Context) error) error {
+		*calls = append(*calls, name+" in")
+		err := fn(ctx)
+		*calls = append(*calls, name+" out")
+		return err
+	})
+}
+
+func TestFirstPolicyIsOutermost(t *testing.T) {
+	calls := []string{}
+	p := Pipeline(trace("a", &calls), trace("b", &calls))
+
+	p.Execute(context.Background(), func(ctx context.Context) error {
+		calls = append(calls, "fn")
+		return nil
+	})
+
+	assert.Equal(t, []string{"a in", "b in", "fn", "b out", "a out"}, calls)
+}
+
+func TestEmptyPipelineCallsFunction(t *testing.T) {
+	err := Pipeline().Execute(context.Background(), func(ctx context.Context) error { return errFailed })
+
+	assert.Equal(t, errFailed, err)
+}
+
+func TestTimeoutCoversAllRetries(t *testing.T) {
+	attempts := int32(0)
+	p := Pipeline(
+		Timeout(50*time.Millisecond),
+		Retry(retry.Policy{MaxAttempts: 100, Backoff: retry.ConstantBackoff(20 * time.Millisecond)}),
+	)
+
+	err := p.Execute(context.Background(), func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return errFailed
+	})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.True(t, atomic.LoadInt32(&attempts) < 5)
+}
+
+func TestBreakerSeesEveryAttempt(t *testing.T) {
+	b := circuit.New("kittens", circuit.Settings{Trip: circuit.ConsecutiveFailures(3)})
+	p := Pipeline(Retry(noWait), Breaker(b))
+
+	err := p.Execute(context.Background(), func(ctx context.Context) error { return errFailed })
+
+	assert.Equal(t, errFailed, err)
+	assert.Equal(t, circuit.Open, b.State())
+}
+
+func TestOpenBreakerStopsRetries(t *testing.T) {
+	b := circuit.New("kittens", circuit.Settings{Trip: circuit.ConsecutiveFailures(1)})
+	p := Pipeline(Retry(noWait), Breaker(b))
+
+	attempts := 0
+	err := p.Execute(context.Background(), func(ctx context.Context) error {
+		attempts++
+		return errFailed
+	})
+
+	assert.Equal(t, circuit.ErrOpen, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestBulkheadOnlyHoldsSlotForAllowedCalls(t *testing.T) {
+	bh := bulkhead.New("kittens", bulkhead.Config{MaxConcurrent: 1})
+	b := circuit.New("kittens", circuit.Settings{Trip: circuit.ConsecutiveFailures(1)})
+	p := Pipeline(Breaker(b), Bulkhead(bh))
+
+	p.Execute(context.Background(), func(ctx context.Context) error { return errFailed })
+	err := p.Execute(context.Background(), func(ctx context.Context) error { return nil })
+
+	assert.Equal(t, circuit.ErrOpen, err)
+	assert.Equal(t, uint64(1), bh.Metrics().Accepted)
+}
+
+func TestHandlerReturnsFinalResponse(t *testing.T) {
+	attempts := 0
+	h := Pipeline(Retry(noWait)).Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(rw, "try again", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(rw, "kittens")
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, rw.Code)
+	assert.Equal(t, "kittens", rw.Body.String())
+}
+
+func TestHandlerDoesNotRetryPost(t *testing.T) {
+	attempts := 0
+	h := Pipeline(Retry(noWait)).Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		attempts++
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "kitten", string(body))
+		http.Error(rw, "broken", http.StatusInternalServerError)
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("kitten")))
+
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, "broken\n", rw.Body.String())
+	assert.Equal(t, 1, attempts)
+}
+
+func TestHandlerSendsBodyToEveryAttempt(t *testing.T) {
+	var bodies []string
+	h := Pipeline(Retry(noWait)).Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		http.Error(rw, "try again", http.StatusBadGateway)
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("kitten")))
+
+	assert.Equal(t, http.StatusBadGateway, rw.Code)
+	assert.Equal(t, []string{"kitten", "kitten", "kitten"}, bodies)
+}
+
+func TestHandlerRejectsBodyLargerThanMaxBodySize(t *testing.T) {
+	called := false
+	c := Pipeline(Retry(noWait))
+	c.MaxBodySize = 4
+	h := c.Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodPut, "/", strings.NewReader("kitten")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	assert.False(t, called)
+}
+
+func TestHandlerReturnsServiceUnavailableWhenBreakerIsOpen(t *testing.T) {
+	b := circuit.New("kittens", circuit.Settings{Trip: circuit.ConsecutiveFailures(1)})
+	h := Pipeline(Breaker(b)).Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		http.Error(rw, "broken", http.StatusInternalServerError)
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rw.Code)
+	assert.Equal(t, "broken\n", rw.Body.String())
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rw.Code)
+}
+
+func TestHandlerReturnsGatewayTimeout(t *testing.T) {
+	h := Pipeline(Timeout(10 * time.Millisecond)).Handler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		fmt.Fprint(rw, "too late")
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	assert.False(t, strings.Contains(rw.Body.String(), "too late"))
+}
+
+func TestRoundTripperRetriesServerErrors(t *testing.T) {
+	attempts := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(rw, "kittens")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Pipeline(Timeout(time.Second), Retry(noWait)).RoundTripper(nil)}
+	resp, err := client.Get(server.URL)
+
+	assert.Nil(t, err)
+	body, _ := ioutil.ReadAll(resp.Body)
+	assert.Equal(t, "kittens", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
+
+func TestRoundTripperReturnsLastResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Pipeline(Retry(noWait)).RoundTripper(nil)}
+	resp, err := client.Get(server.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+}
+
+func TestRoundTripperDoesNotRetryPost(t *testing.T) {
+	attempts := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Pipeline(Retry(noWait)).RoundTripper(nil)}
+	resp, err := client.Post(server.URL, "text/plain", strings.NewReader("kitten"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestRoundTripperRejectsResponseLargerThanMaxBodySize(t *testing.T) {
+	attempts := int32(0)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		fmt.Fprint(rw, "kittens")
+	}))
+	defer server.Close()
+
+	c := Pipeline(Retry(noWait))
+	c.MaxBodySize = 4
+	client := &http.Client{Transport: c.RoundTripper(nil)}
+	_, err := client.Get(server.URL)
+
+	assert.True(t, errors.Is(err, ErrBodyTooLarge))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Backoff is an interface to be implemented by backoff strategies, it
+// returns the delay before the given retry. attempt is 1 for the first retry
+// and previous is the delay which was used before the previous retry.
+type Backoff interface {
+	Delay(attempt int, previous time.Duration) time.Duration
+}
+
+// ConstantBackoff waits the same time before every retry
+type ConstantBackoff time.Duration
+
+// Delay returns the constant delay
+func (c ConstantBackoff) Delay(attempt int, previous time.Duration) time.Duration {
+	return time.Duration(c)
+}
+
+// ExponentialBackoff multiplies the delay by Multiplier for every retry,
+// starting at Base and never exceeding Max. With Jitter the delay is chosen
+// at random between 0 and the exponential delay ("full jitter").
+type ExponentialBackoff struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64 // defaults to 2
+	Jitter     bool
+}
+
+// Delay returns the exponential delay for the given retry
+func (e ExponentialBackoff) Delay(attempt int, previous time.Duration) time.Duration {
+	multiplier := e.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+
+	d := float64(e.Base) * math.Pow(multiplier, float64(attempt-1))
+	if e.Max > 0 && d > float64(e.Max) {
+		d = float64(e.Max)
+	}
+
+	if e.Jitter {
+		d = random() * d
+	}
+
+	return time.Duration(d)
+}
+
+// DecorrelatedJitter chooses every delay at random between Base and three
+// times the previous delay, capped at Max. This spreads the retries of many
+// clients better than exponential backoff while growing at a similar rate.
+type DecorrelatedJitter struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay returns a random delay based on the previous one
+func (d DecorrelatedJitter) Delay(attempt int, previous time.Duration) time.Duration {
+	if previous < d.Base {
+		previous = d.Base
+	}
+
+	upper := float64(previous) * 3
+	delay := float64(d.Base) + random()*(upper-float64(d.Base))
+	if d.Max > 0 && delay > float64(d.Max) {
+		delay = float64(d.Max)
+	}
+
+	return time.Duration(delay)
+}
+
+var (
+	randMutex sync.Mutex
+	rnd       = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+func random() float64 {
+	randMutex.Lock()
+	defer randMutex.Unlock()
+
+	return rnd.Float64()
+}
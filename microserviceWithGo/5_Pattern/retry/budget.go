@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Budget is a token bucket which limits retries to a fraction of the
+// requests so that a failing dependency is not overwhelmed by a retry storm.
+// Every request deposits ratio tokens, tokens also refill at perSecond so
+// that low traffic services can still retry, and every retry withdraws one
+// token.
+type Budget struct {
+	ratio     float64
+	perSecond float64
+	max       float64
+
+	mutex  sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBudget creates a full Budget, for example NewBudget(0.1, 1, 10) allows
+// retries for 10% of the requests plus one retry every second with bursts of
+// up to 10 retries.
+func NewBudget(ratio, perSecond, max float64) *Budget {
+	return &Budget{
+		ratio:     ratio,
+		perSecond: perSecond,
+		max:       max,
+		tokens:    max,
+		last:      time.Now(),
+	}
+}
+
+// Deposit records a request
+func (b *Budget) Deposit() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	b.tokens += b.ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// Withdraw returns true when a retry is allowed and takes a token for it
+func (b *Budget) Withdraw() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// Tokens returns the number of retries currently available
+func (b *Budget) Tokens() float64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refill()
+	return b.tokens
+}
+
+func (b *Budget) refill() {
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.perSecond
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+	b.last = now
+}
@@ -0,0 +1,151 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultMaxAttempts is the number of attempts made when
+// Policy.MaxAttempts is not set.
+const DefaultMaxAttempts = 3
+
+// DefaultMaxRetryAfter caps the delay requested by a RetryAfter error when
+// Policy.MaxRetryAfter is not set.
+const DefaultMaxRetryAfter = 10 * time.Second
+
+// DefaultBackoff is used when Policy.Backoff is not set
+var DefaultBackoff Backoff = ExponentialBackoff{Base: 100 * time.Millisecond, Max: 10 * time.Second, Jitter: true}
+
+// Classifier returns true when the error should be retried
+type Classifier func(err error) bool
+
+// DefaultClassifier retries every error apart from permanent errors and
+// errors caused by the context being canceled or timing out.
+func DefaultClassifier(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var p *permanentError
+	if errors.As(err, &p) {
+		return false
+	}
+
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (p *permanentError) Error() string { return p.err.Error() }
+func (p *permanentError) Unwrap() error { return p.err }
+
+// Permanent wraps an error so that DefaultClassifier does not retry it
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// RetryAfter is implemented by errors which know how long to wait before
+// the next attempt, for example from a Retry-After header. The delay
+// replaces the one from the Backoff but never exceeds Policy.MaxRetryAfter.
+type RetryAfter interface {
+	RetryAfter() time.Duration
+}
+
+// Policy describes how a function is retried, zero values are replaced with
+// defaults.
+type Policy struct {
+	// MaxAttempts includes the first attempt, defaults to
+	// DefaultMaxAttempts.
+	MaxAttempts int
+	// Backoff decides the delay between attempts, defaults to
+	// DefaultBackoff.
+	Backoff Backoff
+	// MaxRetryAfter caps the delay requested by a RetryAfter error, defaults
+	// to DefaultMaxRetryAfter.
+	MaxRetryAfter time.Duration
+	// Classifier decides which errors are retried, defaults to
+	// DefaultClassifier.
+	Classifier Classifier
+	// Budget limits the number of retries across all calls, retries are not
+	// limited when nil.
+	Budget *Budget
+}
+
+// Do calls fn until it succeeds, returns an error which should not be
+// retried or the attempts or budget are exhausted. The error of the last
+// attempt is returned. The context is passed to fn and canceling it stops
+// the retries, in which case the error of the context is returned.
+func (p Policy) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := p.MaxAttempts
+	if attempts <= 0 {
+		attempts = DefaultMaxAttempts
+	}
+
+	backoff := p.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	maxRetryAfter := p.MaxRetryAfter
+	if maxRetryAfter <= 0 {
+		maxRetryAfter = DefaultMaxRetryAfter
+	}
+
+	classifier := p.Classifier
+	if classifier == nil {
+		classifier = DefaultClassifier
+	}
+
+	if p.Budget != nil {
+		p.Budget.Deposit()
+	}
+
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil || !classifier(err) || attempt >= attempts || ctx.Err() != nil {
+			return err
+		}
+
+		if p.Budget != nil && !p.Budget.Withdraw() {
+			return err
+		}
+
+		delay = backoff.Delay(attempt, delay)
+		var ra RetryAfter
+		if errors.As(err, &ra) && ra.RetryAfter() > 0 {
+			delay = ra.RetryAfter()
+			if delay > maxRetryAfter {
+				delay = maxRetryAfter
+			}
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// sleep waits for the given time or until the context is done
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,188 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailed = errors.New("failed")
+
+func TestExponentialBackoffGrowsUntilMax(t *testing.T) {
+	b := ExponentialBackoff{Base: 10 * time.Millisecond, Max: 50 * time.Millisecond}
+
+	assert.Equal(t, 10*time.Millisecond, b.Delay(1, 0))
+	assert.Equal(t, 20*time.Millisecond, b.Delay(2, 0))
+	assert.Equal(t, 40*time.Millisecond, b.Delay(3, 0))
+	assert.Equal(t, 50*time.Millisecond, b.Delay(4, 0))
+}
+
+func TestExponentialBackoffWithJitterStaysBelowDelay(t *testing.T) {
+	b := ExponentialBackoff{Base: 10 * time.Millisecond, Max: time.Second, Jitter: true}
+
+	for i := 0; i < 100; i++ {
+		d := b.Delay(3, 0)
+		assert.True(t, d >= 0 && d <= 40*time.Millisecond, "delay %v out of range", d)
+	}
+}
+
+func TestDecorrelatedJitterStaysWithinBounds(t *testing.T) {
+	b := DecorrelatedJitter{Base: 10 * time.Millisecond, Max: 100 * time.Millisecond}
+
+	previous := time.Duration(0)
+	for i := 1; i < 100; i++ {
+		d := b.Delay(i, previous)
+		upper := 3 * previous
+		if upper < 30*time.Millisecond {
+			upper = 30 * time.Millisecond
+		}
+		if upper > b.Max {
+			upper = b.Max
+		}
+
+		assert.True(t, d >= b.Base && d <= upper, "delay %v out of range", d)
+		previous = d
+	}
+}
+
+func TestDoRetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 5, Backoff: ConstantBackoff(time.Millisecond)}
+
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return errFailed
+		}
+		return nil
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDoReturnsLastErrorWhenAttemptsExhausted(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)}
+
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errFailed
+	})
+
+	assert.Equal(t, errFailed, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestDoDoesNotRetryPermanentErrors(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)}
+
+	err := p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return Permanent(errFailed)
+	})
+
+	assert.True(t, errors.Is(err, errFailed))
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoUsesClassifier(t *testing.T) {
+	calls := 0
+	p := Policy{
+		MaxAttempts: 3,
+		Backoff:     ConstantBackoff(time.Millisecond),
+		Classifier:  func(err error) bool { return err.Error() == "temporary" },
+	}
+
+	p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errFailed
+	})
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestDoStopsSleepingWhenContextIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	p := Policy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Minute)}
+
+	start := time.Now()
+	err := p.Do(ctx, func(ctx context.Context) error { return errFailed })
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+type retryAfterError struct{}
+
+func (retryAfterError) Error() string             { return "slow down" }
+func (retryAfterError) RetryAfter() time.Duration { return 30 * time.Millisecond }
+
+func TestDoWaitsForRetryAfter(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 2, Backoff: ConstantBackoff(time.Millisecond)}
+
+	start := time.Now()
+	p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return retryAfterError{}
+	})
+
+	assert.Equal(t, 2, calls)
+	assert.True(t, time.Since(start) >= 30*time.Millisecond)
+}
+
+func TestDoCapsRetryAfter(t *testing.T) {
+	calls := 0
+	p := Policy{MaxAttempts: 2, Backoff: ConstantBackoff(time.Millisecond), MaxRetryAfter: 5 * time.Millisecond}
+
+	start := time.Now()
+	p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return retryAfterError{}
+	})
+
+	assert.Equal(t, 2, calls)
+	assert.True(t, time.Since(start) < 30*time.Millisecond)
+}
+
+func TestDoStopsRetryingWhenBudgetIsExhausted(t *testing.T) {
+	budget := NewBudget(0, 0, 2)
+	p := Policy{MaxAttempts: 10, Backoff: ConstantBackoff(0), Budget: budget}
+
+	calls := 0
+	p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errFailed
+	})
+
+	assert.Equal(t, 3, calls, "first attempt plus two retries from the budget")
+
+	calls = 0
+	p.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		return errFailed
+	})
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestBudgetRefillsFromRequestsAndTime(t *testing.T) {
+	budget := NewBudget(0.5, 100, 10)
+	for budget.Withdraw() {
+	}
+
+	budget.Deposit()
+	budget.Deposit()
+	assert.True(t, budget.Tokens() >= 1)
+
+	for budget.Withdraw() {
+	}
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, budget.Tokens() >= 1)
+}
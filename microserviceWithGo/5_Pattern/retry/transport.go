@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryableStatus returns a function which reports whether a response has
+// one of the given status codes, use it for Transport.RetryableStatus.
+func RetryableStatus(codes ...int) func(resp *http.Response) bool {
+	return func(resp *http.Response) bool {
+		for _, c := range codes {
+			if resp.StatusCode == c {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// DefaultRetryableStatus retries responses which say the server is
+// temporarily unable to handle the request.
+var DefaultRetryableStatus = RetryableStatus(
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+)
+
+// StatusError is passed to Policy.Classifier for responses with a retryable
+// status code, it is never returned by RoundTrip.
+type StatusError struct {
+	StatusCode int
+	retryAfter time.Duration
+}
+
+func (s *StatusError) Error() string {
+	return fmt.Sprintf("retry: server returned %v %v", s.StatusCode, http.StatusText(s.StatusCode))
+}
+
+// RetryAfter returns the delay from the Retry-After header of the response
+func (s *StatusError) RetryAfter() time.Duration {
+	return s.retryAfter
+}
+
+// Transport is an http.RoundTripper which retries idempotent requests with
+// the given Policy when the request fails or the response has a retryable
+// status code. When all attempts return a retryable status code the last
+// response is returned.
+type Transport struct {
+	Policy Policy
+	// Base sends the requests, defaults to http.DefaultTransport.
+	Base http.RoundTripper
+	// RetryableStatus decides which responses are retried, defaults to
+	// DefaultRetryableStatus.
+	RetryableStatus func(resp *http.Response) bool
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	retryable := t.RetryableStatus
+	if retryable == nil {
+		retryable = DefaultRetryableStatus
+	}
+
+	if !Idempotent(req) {
+		return base.RoundTrip(req)
+	}
+
+	var resp *http.Response
+	attempt := 0
+
+	err := t.Policy.Do(req.Context(), func(ctx context.Context) error {
+		// the response of the previous attempt is discarded before retrying
+		if resp != nil {
+			drain(resp.Body)
+			resp = nil
+		}
+
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return Permanent(err)
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+		attempt++
+
+		var err error
+		resp, err = base.RoundTrip(r)
+		if err != nil {
+			return err
+		}
+
+		if retryable(resp) {
+			return &StatusError{StatusCode: resp.StatusCode, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
+		}
+
+		return nil
+	})
+
+	// all attempts returned a retryable status, the caller gets the last
+	// response rather than our error
+	if resp != nil {
+		return resp, nil
+	}
+
+	return nil, err
+}
+
+func drain(body io.ReadCloser) {
+	io.Copy(ioutil.Discard, io.LimitReader(body, 4096))
+	body.Close()
+}
+
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return time.Until(t)
+	}
+
+	return 0
+}
+
+// Idempotent reports whether the request is idempotent and its body can be
+// sent again, so that it is safe to retry it.
+func Idempotent(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	_, hasKey := req.Header["Idempotency-Key"]
+	_, hasXKey := req.Header["X-Idempotency-Key"]
+
+	return hasKey || hasXKey
+}
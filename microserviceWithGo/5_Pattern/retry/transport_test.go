@@ -0,0 +1,104 @@
+package retry
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFlakyServer returns the given status codes in turn and then 200 OK
+func newFlakyServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	var calls int32
+	s := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := int(atomic.AddInt32(&calls, 1))
+		body, _ := ioutil.ReadAll(r.Body)
+
+		if n <= len(statuses) {
+			rw.WriteHeader(statuses[n-1])
+			return
+		}
+
+		rw.Write(body)
+	}))
+	t.Cleanup(s.Close)
+
+	return s, &calls
+}
+
+func newClient() *http.Client {
+	return &http.Client{Transport: &Transport{
+		Policy: Policy{MaxAttempts: 3, Backoff: ConstantBackoff(time.Millisecond)},
+	}}
+}
+
+func TestTransportRetriesRetryableStatus(t *testing.T) {
+	s, calls := newFlakyServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+
+	req, _ := http.NewRequest(http.MethodPut, s.URL, strings.NewReader("Garfield"))
+	resp, err := newClient().Do(req)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Equal(t, "Garfield", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestTransportReturnsLastResponseWhenAttemptsExhausted(t *testing.T) {
+	s, calls := newFlakyServer(t, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+
+	resp, err := newClient().Get(s.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestTransportDoesNotRetryOtherStatus(t *testing.T) {
+	s, calls := newFlakyServer(t, http.StatusInternalServerError)
+
+	resp, err := newClient().Get(s.URL)
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransportDoesNotRetryNonIdempotentRequests(t *testing.T) {
+	s, calls := newFlakyServer(t, http.StatusServiceUnavailable)
+
+	resp, err := newClient().Post(s.URL, "text/plain", strings.NewReader("Garfield"))
+
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	resp.Body.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestTransportRetriesConnectionErrors(t *testing.T) {
+	s := httptest.NewServer(http.NotFoundHandler())
+	s.Close()
+
+	start := time.Now()
+	_, err := newClient().Get(s.URL)
+
+	assert.NotNil(t, err)
+	assert.True(t, time.Since(start) >= 2*time.Millisecond)
+}
+
+func TestParsesRetryAfterHeader(t *testing.T) {
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+
+	d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, d > 50*time.Second && d <= time.Minute)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/5_Pattern/timeout"
+)
+
+func main() {
+	switch os.Args[1] {
+	case "slow":
+		makeNormalRequest()
+	case "timeout":
+		makeTimeoutRequest()
+	}
+}
+
+func makeNormalRequest() {
+	slowFunction(context.Background())
+}
+
+func makeTimeoutRequest() {
+	err := timeout.Run(context.Background(), 1*time.Second, slowFunction)
+
+	var te *timeout.TimeoutError
+	switch {
+	case errors.As(err, &te):
+		// slowFunction 이 컨텍스트 취소를 확인하기 때문에 고루틴도 함께 종료된다.
+		fmt.Println("Timeout, function exited:", te.Exited)
+	default:
+		fmt.Println(err)
+	}
+}
+
+func slowFunction(ctx context.Context) error {
+	for i := 0; i < 100; i++ {
+		fmt.Println("Loop: ", i)
+
+		select {
+		case <-time.After(1 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package timeout
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Handler is middleware which gives every request a deadline, the request
+// context is canceled at the deadline and http.StatusGatewayTimeout is
+// returned when the next handler has not finished. Routes can have their own
+// timeout, the longest matching path prefix wins.
+type Handler struct {
+	timeout time.Duration
+	handler http.Handler
+
+	mutex  sync.RWMutex
+	routes map[string]time.Duration
+}
+
+// NewHandler creates a new instance of the Handler with the given default
+// timeout
+func NewHandler(timeout time.Duration, next http.Handler) *Handler {
+	return &Handler{
+		timeout: timeout,
+		handler: next,
+		routes:  map[string]time.Duration{},
+	}
+}
+
+// SetRouteTimeout sets the timeout for requests whose path starts with prefix
+func (h *Handler) SetRouteTimeout(prefix string, timeout time.Duration) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.routes[prefix] = timeout
+}
+
+// Timeout returns the timeout for the given path
+func (h *Handler) Timeout(path string) time.Duration {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	timeout, longest := h.timeout, -1
+	for prefix, d := range h.routes {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			timeout, longest = d, len(prefix)
+		}
+	}
+
+	return timeout
+}
+
+func (h *Handler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), h.Timeout(r.URL.Path))
+	defer cancel()
+	r = r.WithContext(ctx)
+
+	done := make(chan struct{})
+	panics := make(chan interface{}, 1)
+	tw := &timeoutWriter{ctx: ctx, header: make(http.Header)}
+
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				panics <- p
+			}
+		}()
+
+		h.handler.ServeHTTP(tw, r)
+		close(done)
+	}()
+
+	select {
+	case p := <-panics:
+		panic(p)
+	case <-done:
+		tw.mutex.Lock()
+		defer tw.mutex.Unlock()
+
+		for k, v := range tw.header {
+			rw.Header()[k] = v
+		}
+		if tw.status == 0 {
+			tw.status = http.StatusOK
+		}
+		rw.WriteHeader(tw.status)
+		rw.Write(tw.body.Bytes())
+	case <-ctx.Done():
+		tw.mutex.Lock()
+		defer tw.mutex.Unlock()
+
+		tw.timedOut = true
+		if ctx.Err() == context.DeadlineExceeded {
+			http.Error(rw, "Gateway Timeout", http.StatusGatewayTimeout)
+		}
+	}
+}
+
+// timeoutWriter buffers the response so that nothing is written to the
+// client by the next handler once the timeout response has been sent
+type timeoutWriter struct {
+	ctx      context.Context
+	mutex    sync.Mutex
+	header   http.Header
+	body     bytes.Buffer
+	status   int
+	timedOut bool
+}
+
+func (t *timeoutWriter) Header() http.Header {
+	return t.header
+}
+
+func (t *timeoutWriter) Write(p []byte) (int, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	// the handler may see the deadline before ServeHTTP does
+	if t.timedOut || t.ctx.Err() != nil {
+		return 0, http.ErrHandlerTimeout
+	}
+	if t.status == 0 {
+		t.status = http.StatusOK
+	}
+
+	return t.body.Write(p)
+}
+
+func (t *timeoutWriter) WriteHeader(status int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if t.timedOut || t.status != 0 {
+		return
+	}
+
+	t.status = status
+}
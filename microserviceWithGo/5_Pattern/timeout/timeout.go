@@ -0,0 +1,116 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimedOut is matched by the errors returned by Run when the function did
+// not complete in time, use errors.Is(err, ErrTimedOut).
+var ErrTimedOut = errors.New("timeout: timed out waiting for function to finish")
+
+// DefaultGrace is the time Run waits for the function to return after its
+// context was canceled when Runner.Grace is not set.
+const DefaultGrace = 100 * time.Millisecond
+
+// TimeoutError is returned by Run when the function did not finish before
+// the timeout.
+type TimeoutError struct {
+	Timeout time.Duration
+	// Exited is true when the function returned within the grace period
+	// after its context was canceled. When false the goroutine running the
+	// function is still alive because the function ignores its context.
+	Exited bool
+	// Done is closed once the function has returned
+	Done <-chan struct{}
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Exited {
+		return fmt.Sprintf("timeout: function did not finish within %v", e.Timeout)
+	}
+
+	return fmt.Sprintf("timeout: function did not finish within %v and is still running", e.Timeout)
+}
+
+// Is makes errors.Is(err, ErrTimedOut) and
+// errors.Is(err, context.DeadlineExceeded) true
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimedOut || target == context.DeadlineExceeded
+}
+
+// Runner runs functions with a timeout. Unlike a deadline which only stops
+// waiting, the function is given a context which is canceled at the timeout
+// so that it can stop its work.
+type Runner struct {
+	Timeout time.Duration
+	// Grace is the time to wait for the function to return after its
+	// context was canceled, defaults to DefaultGrace.
+	Grace time.Duration
+}
+
+// New creates a Runner with the given timeout
+func New(timeout time.Duration) Runner {
+	return Runner{Timeout: timeout}
+}
+
+// Run calls fn with a context which is canceled after the timeout or when
+// ctx is done. The error of fn is returned when it finishes in time,
+// otherwise a *TimeoutError, or the error of ctx when ctx was canceled by
+// the caller.
+func (r Runner) Run(ctx context.Context, fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, r.Timeout)
+	defer cancel()
+
+	done := make(chan struct{})
+	result := make(chan error, 1)
+
+	go func() {
+		defer close(done)
+		result <- fn(ctx)
+	}()
+
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		// select picks at random when fn finished right at the deadline
+		select {
+		case err := <-result:
+			return err
+		default:
+		}
+	}
+
+	// the work is told to stop, give it the chance to do so
+	cancel()
+
+	grace := r.Grace
+	if grace <= 0 {
+		grace = DefaultGrace
+	}
+
+	exited := true
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+	case <-timer.C:
+		exited = false
+	}
+
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+
+	return &TimeoutError{Timeout: r.Timeout, Exited: exited, Done: done}
+}
+
+// Run calls fn with a context which is canceled after the given timeout, see
+// Runner.Run
+func Run(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	return New(timeout).Run(ctx, fn)
+}
@@ -0,0 +1,135 @@
+package timeout
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errFailed = errors.New("failed")
+
+func TestRunReturnsErrorOfFunction(t *testing.T) {
+	err := Run(context.Background(), time.Second, func(ctx context.Context) error {
+		return errFailed
+	})
+
+	assert.Equal(t, errFailed, err)
+}
+
+func TestRunCancelsContextOfCooperativeFunction(t *testing.T) {
+	err := Run(context.Background(), 10*time.Millisecond, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	var te *TimeoutError
+	assert.True(t, errors.As(err, &te))
+	assert.True(t, errors.Is(err, ErrTimedOut))
+	assert.True(t, te.Exited)
+}
+
+func TestRunReportsFunctionWhichIgnoresContext(t *testing.T) {
+	release := make(chan struct{})
+	r := Runner{Timeout: 10 * time.Millisecond, Grace: 10 * time.Millisecond}
+
+	err := r.Run(context.Background(), func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	var te *TimeoutError
+	assert.True(t, errors.As(err, &te))
+	assert.False(t, te.Exited)
+
+	close(release)
+	select {
+	case <-te.Done:
+	case <-time.After(time.Second):
+		t.Fatal("Done should be closed once the function returns")
+	}
+}
+
+func TestRunReturnsErrorWhenParentIsCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	err := Run(ctx, time.Minute, func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	assert.Equal(t, context.Canceled, err)
+}
+
+func newSlowHandler(d time.Duration) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(d):
+			rw.Header().Set("X-Kitten", "Garfield")
+			rw.WriteHeader(http.StatusCreated)
+			rw.Write([]byte("done"))
+		case <-r.Context().Done():
+		}
+	})
+}
+
+func TestHandlerPassesThroughFastResponses(t *testing.T) {
+	h := NewHandler(time.Second, newSlowHandler(0))
+	rw := httptest.NewRecorder()
+
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/kittens", nil))
+
+	assert.Equal(t, http.StatusCreated, rw.Code)
+	assert.Equal(t, "Garfield", rw.Header().Get("X-Kitten"))
+	assert.Equal(t, "done", rw.Body.String())
+}
+
+func TestHandlerReturnsGatewayTimeoutForSlowResponses(t *testing.T) {
+	h := NewHandler(10*time.Millisecond, newSlowHandler(time.Second))
+	rw := httptest.NewRecorder()
+
+	start := time.Now()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/kittens", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+	assert.Empty(t, rw.Header().Get("X-Kitten"))
+	assert.True(t, time.Since(start) < 500*time.Millisecond)
+}
+
+func TestHandlerUsesLongestMatchingRouteTimeout(t *testing.T) {
+	h := NewHandler(10*time.Millisecond, newSlowHandler(50*time.Millisecond))
+	h.SetRouteTimeout("/reports", time.Second)
+	h.SetRouteTimeout("/reports/fast", 10*time.Millisecond)
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/reports/monthly", nil))
+	assert.Equal(t, http.StatusCreated, rw.Code)
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/reports/fast", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+
+	rw = httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/kittens", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, rw.Code)
+}
+
+func TestHandlerRejectsWritesAfterTimeout(t *testing.T) {
+	writeErr := make(chan error, 1)
+	h := NewHandler(10*time.Millisecond, http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		_, err := rw.Write([]byte("too late"))
+		writeErr <- err
+	}))
+
+	rw := httptest.NewRecorder()
+	h.ServeHTTP(rw, httptest.NewRequest("GET", "/", nil))
+
+	assert.Equal(t, http.ErrHandlerTimeout, <-writeErr)
+	assert.NotContains(t, rw.Body.String(), "too late")
+}
@@ -1,17 +1,56 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"time"
 
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/interceptor"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittenerr"
 	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/tlsconfig"
 	context "golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 func main() {
 	// 서버에 연결한 다음 요청을 초기화
-	conn, err := grpc.Dial("127.0.0.1:9000", grpc.WithInsecure()) //전송 보안 비활성화
+	token := os.Getenv("KITTENS_TOKEN")
+	if token == "" {
+		token = "kittens"
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(interceptor.UnaryClientDeadline(5 * time.Second)), // 기본 타임아웃
+	}
+
+	// CA 가 주어지면 TLS 사용, 인증서와 키가 있으면 mTLS
+	if ca := os.Getenv("KITTENS_TLS_CA"); ca != "" {
+		reloader, err := tlsconfig.NewReloader(tlsconfig.Files{
+			Cert: os.Getenv("KITTENS_TLS_CERT"),
+			Key:  os.Getenv("KITTENS_TLS_KEY"),
+			CA:   ca,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		opts = append(opts,
+			grpc.WithTransportCredentials(credentials.NewTLS(reloader.ClientConfig("localhost"))),
+			grpc.WithPerRPCCredentials(interceptor.TokenCredentials{Token: token}),
+		)
+	} else {
+		opts = append(opts,
+			grpc.WithInsecure(), //전송 보안 비활성화
+			grpc.WithPerRPCCredentials(interceptor.TokenCredentials{Token: token, AllowInsecure: true}),
+		)
+	}
+
+	conn, err := grpc.Dial("127.0.0.1:9000", opts...)
 	if err != nil {
 		log.Fatal("Unable to create connection to server: ", err)
 	}
@@ -20,8 +59,59 @@ func main() {
 	response, err := client.Hello(context.Background(), &proto.Request{Name: "Nic"})
 
 	if err != nil {
-		log.Fatal("Error calling service: ", err)
+		log.Fatal("Error calling service: ", kittenerr.Decode(err))
 	}
 
 	fmt.Println(response.Msg)
+
+	// 새끼 고양이 생성 후 이름으로 검색
+	kitten, err := client.Create(context.Background(), &proto.CreateRequest{
+		Kitten: &proto.Kitten{Name: "Tom", Weight: 4.2},
+	})
+	if err != nil {
+		log.Fatal("Error creating kitten: ", kittenerr.Decode(err))
+	}
+
+	fmt.Println("Created kitten:", kitten.Id)
+
+	search, err := client.Search(context.Background(), &proto.SearchRequest{Name: "Tom"})
+	if err != nil {
+		log.Fatal("Error searching kittens: ", kittenerr.Decode(err))
+	}
+
+	for _, k := range search.Kittens {
+		fmt.Printf("%v: %v (%v)\n", k.Id, k.Name, k.Weight)
+	}
+
+	// 에러 상세 정보를 타입이 있는 에러로 변환해서 처리
+	_, err = client.Get(context.Background(), &proto.GetRequest{Id: "404"})
+
+	var notFound *kittenerr.NotFoundError
+	var unavailable *kittenerr.UnavailableError
+	switch err := kittenerr.Decode(err); {
+	case errors.As(err, &notFound):
+		fmt.Printf("No %v with id %v\n", notFound.Type, notFound.Name)
+	case errors.As(err, &unavailable):
+		log.Fatalf("Kitten store unavailable, try again in %v", unavailable.RetryAfter)
+	case err != nil:
+		log.Fatal("Error getting kitten: ", err)
+	}
+
+	// 전체 목록을 스트림으로 수신
+	stream, err := client.ListKittens(context.Background(), &proto.ListKittensRequest{})
+	if err != nil {
+		log.Fatal("Error listing kittens: ", err)
+	}
+
+	for {
+		k, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal("Error receiving kitten: ", err)
+		}
+
+		fmt.Printf("%v: %v (%v)\n", k.Id, k.Name, k.Weight)
+	}
 }
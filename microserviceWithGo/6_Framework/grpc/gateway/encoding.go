@@ -0,0 +1,143 @@
+package gateway
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	protobuf "google.golang.org/protobuf/proto"
+)
+
+var (
+	marshaler   = protojson.MarshalOptions{EmitUnpopulated: true}
+	unmarshaler = protojson.UnmarshalOptions{DiscardUnknown: true}
+)
+
+// HTTPStatus returns the HTTP status for a gRPC status code
+func HTTPStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499 // client closed request
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorBody is the JSON body of failed calls
+type errorBody struct {
+	Code    codes.Code `json:"code"`
+	Status  string     `json:"status"`
+	Message string     `json:"message"`
+}
+
+func newErrorBody(s *status.Status) errorBody {
+	return errorBody{Code: s.Code(), Status: s.Code().String(), Message: s.Message()}
+}
+
+func writeError(rw http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	writeStatus(rw, HTTPStatus(s.Code()), s)
+}
+
+func writeStatus(rw http.ResponseWriter, code int, s *status.Status) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	json.NewEncoder(rw).Encode(newErrorBody(s))
+}
+
+// respond writes m as JSON with the given status or the error
+func respond(rw http.ResponseWriter, code int, m protobuf.Message, err error) {
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+
+	body, err := marshaler.Marshal(m)
+	if err != nil {
+		writeError(rw, status.Error(codes.Internal, err.Error()))
+		return
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	rw.Write(body)
+}
+
+// startNDJSON sends the status and headers of an NDJSON stream straight away
+// so that the client knows the stream has been accepted before the first
+// message arrives
+func startNDJSON(rw http.ResponseWriter) {
+	rw.Header().Set("Content-Type", "application/x-ndjson")
+	rw.WriteHeader(http.StatusOK)
+	if flusher, ok := rw.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// streamNDJSON writes every message returned by recv on its own line and
+// flushes it straight away, started is true when startNDJSON has already
+// been called. An error before the first message is returned as a normal
+// error response, later errors are written as a last line of the form
+// {"error": {...}} as the status has already been sent.
+func streamNDJSON(rw http.ResponseWriter, started bool, recv func() (protobuf.Message, error)) {
+	flusher, _ := rw.(http.Flusher)
+
+	for {
+		m, err := recv()
+		if err == io.EOF {
+			if !started {
+				startNDJSON(rw)
+			}
+			return
+		}
+		if err != nil {
+			if !started {
+				writeError(rw, err)
+				return
+			}
+
+			json.NewEncoder(rw).Encode(struct {
+				Error errorBody `json:"error"`
+			}{newErrorBody(status.Convert(err))})
+			return
+		}
+
+		body, err := marshaler.Marshal(m)
+		if err != nil {
+			return
+		}
+
+		if !started {
+			startNDJSON(rw)
+			started = true
+		}
+
+		rw.Write(append(body, '\n'))
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+}
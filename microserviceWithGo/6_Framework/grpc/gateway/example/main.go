@@ -0,0 +1,28 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/gateway"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/interceptor"
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	// 게이트웨이는 Authorization 헤더를 그대로 서버에 전달
+	// curl -H "Authorization: Bearer kittens" localhost:8080/v1/kittens
+	conn, err := grpc.Dial("127.0.0.1:9000",
+		grpc.WithInsecure(),
+		grpc.WithChainUnaryInterceptor(interceptor.UnaryClientDeadline(5*time.Second)),
+	)
+	if err != nil {
+		log.Fatal("Unable to create connection to server: ", err)
+	}
+	defer conn.Close()
+
+	log.Println("Starting gateway on :8080")
+	log.Fatal(http.ListenAndServe(":8080", gateway.New(proto.NewKittensClient(conn))))
+}
@@ -0,0 +1,201 @@
+// Package gateway exposes the Kittens gRPC service as a REST API with JSON
+// bodies for clients which can not speak gRPC.
+//
+//	POST   /v1/hello           Hello, body {"name": "..."}
+//	GET    /v1/search?name=    Search
+//	GET    /v1/kittens?name=   ListKittens, streamed as NDJSON
+//	POST   /v1/kittens         Create, body is a kitten
+//	GET    /v1/kittens/{id}    Get
+//	PUT    /v1/kittens/{id}    Update, body is a kitten
+//	DELETE /v1/kittens/{id}    Delete
+//	GET    /v1/watch?name=     WatchKittens, streamed as NDJSON
+//
+// The Authorization header is passed on to the service as metadata.
+package gateway
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// Gateway is an http.Handler which translates REST calls into calls of the
+// Kittens service
+type Gateway struct {
+	client proto.KittensClient
+	mux    *http.ServeMux
+}
+
+// New creates a Gateway which sends the calls with the given client
+func New(client proto.KittensClient) *Gateway {
+	g := &Gateway{client: client, mux: http.NewServeMux()}
+
+	g.mux.HandleFunc("/v1/hello", g.hello)
+	g.mux.HandleFunc("/v1/search", g.search)
+	g.mux.HandleFunc("/v1/kittens", g.kittens)
+	g.mux.HandleFunc("/v1/kittens/", g.kitten)
+	g.mux.HandleFunc("/v1/watch", g.watch)
+
+	return g
+}
+
+func (g *Gateway) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(rw, r)
+}
+
+func (g *Gateway) hello(rw http.ResponseWriter, r *http.Request) {
+	if !allow(rw, r, http.MethodPost) {
+		return
+	}
+
+	request := &proto.Request{}
+	if !decode(rw, r, request) {
+		return
+	}
+
+	response, err := g.client.Hello(outgoing(r), request)
+	respond(rw, http.StatusOK, response, err)
+}
+
+func (g *Gateway) search(rw http.ResponseWriter, r *http.Request) {
+	if !allow(rw, r, http.MethodGet) {
+		return
+	}
+
+	response, err := g.client.Search(outgoing(r), &proto.SearchRequest{Name: r.URL.Query().Get("name")})
+	respond(rw, http.StatusOK, response, err)
+}
+
+func (g *Gateway) kittens(rw http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		stream, err := g.client.ListKittens(outgoing(r), &proto.ListKittensRequest{Name: r.URL.Query().Get("name")})
+		if err != nil {
+			writeError(rw, err)
+			return
+		}
+
+		streamNDJSON(rw, false, func() (protobuf.Message, error) { return stream.Recv() })
+	case http.MethodPost:
+		kitten := &proto.Kitten{}
+		if !decode(rw, r, kitten) {
+			return
+		}
+
+		response, err := g.client.Create(outgoing(r), &proto.CreateRequest{Kitten: kitten})
+		respond(rw, http.StatusCreated, response, err)
+	default:
+		allow(rw, r, http.MethodGet, http.MethodPost)
+	}
+}
+
+func (g *Gateway) kitten(rw http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/v1/kittens/")
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(rw, r)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		response, err := g.client.Get(outgoing(r), &proto.GetRequest{Id: id})
+		respond(rw, http.StatusOK, response, err)
+	case http.MethodPut:
+		kitten := &proto.Kitten{}
+		if !decode(rw, r, kitten) {
+			return
+		}
+		kitten.Id = id
+
+		response, err := g.client.Update(outgoing(r), &proto.UpdateRequest{Kitten: kitten})
+		respond(rw, http.StatusOK, response, err)
+	case http.MethodDelete:
+		response, err := g.client.Delete(outgoing(r), &proto.DeleteRequest{Id: id})
+		respond(rw, http.StatusOK, response, err)
+	default:
+		allow(rw, r, http.MethodGet, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// watch streams change events until the client goes away
+func (g *Gateway) watch(rw http.ResponseWriter, r *http.Request) {
+	if !allow(rw, r, http.MethodGet) {
+		return
+	}
+
+	stream, err := g.client.WatchKittens(outgoing(r))
+	if err != nil {
+		writeError(rw, err)
+		return
+	}
+	if err := stream.Send(&proto.WatchRequest{Name: r.URL.Query().Get("name")}); err != nil {
+		writeError(rw, err)
+		return
+	}
+
+	// the server sends its headers once it has subscribed, pass them on so
+	// the client knows the watch started before any change happens. A stream
+	// which the server rejected straight away has no headers, only a status.
+	md, err := stream.Header()
+	if err == nil && len(md) == 0 {
+		_, err = stream.Recv()
+	}
+	if err != nil && err != io.EOF {
+		writeError(rw, err)
+		return
+	}
+	startNDJSON(rw)
+	if err == io.EOF {
+		return
+	}
+	streamNDJSON(rw, true, func() (protobuf.Message, error) { return stream.Recv() })
+}
+
+// outgoing returns the context of the request with the Authorization header
+// as gRPC metadata
+func outgoing(r *http.Request) context.Context {
+	ctx := r.Context()
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", auth)
+	}
+
+	return ctx
+}
+
+func allow(rw http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, m := range methods {
+		if r.Method == m {
+			return true
+		}
+	}
+
+	rw.Header().Set("Allow", strings.Join(methods, ", "))
+	writeStatus(rw, http.StatusMethodNotAllowed, status.Newf(codes.Unimplemented, "method %v not allowed", r.Method))
+
+	return false
+}
+
+// decode reads the JSON body of the request into m
+func decode(rw http.ResponseWriter, r *http.Request, m protobuf.Message) bool {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1<<20))
+	if err != nil {
+		writeError(rw, status.Error(codes.InvalidArgument, err.Error()))
+		return false
+	}
+
+	if err := unmarshaler.Unmarshal(body, m); err != nil {
+		writeError(rw, status.Errorf(codes.InvalidArgument, "invalid body: %v", err))
+		return false
+	}
+
+	return true
+}
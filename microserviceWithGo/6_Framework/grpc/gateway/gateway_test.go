@@ -0,0 +1,239 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// fakeServer keeps kittens in a map, ListKittens fails after the kittens
+// when failList is set
+type fakeServer struct {
+	proto.UnimplementedKittensServer
+	kittens  map[string]*proto.Kitten
+	failList bool
+}
+
+func (s *fakeServer) Hello(ctx context.Context, r *proto.Request) (*proto.Response, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return &proto.Response{Msg: "Hello " + r.Name + " " + strings.Join(md.Get("authorization"), "")}, nil
+}
+
+func (s *fakeServer) Get(ctx context.Context, r *proto.GetRequest) (*proto.Kitten, error) {
+	k, ok := s.kittens[r.Id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "kitten %v not found", r.Id)
+	}
+
+	return k, nil
+}
+
+func (s *fakeServer) Create(ctx context.Context, r *proto.CreateRequest) (*proto.Kitten, error) {
+	if _, ok := s.kittens[r.Kitten.Id]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "kitten %v exists", r.Kitten.Id)
+	}
+	s.kittens[r.Kitten.Id] = r.Kitten
+
+	return r.Kitten, nil
+}
+
+func (s *fakeServer) ListKittens(r *proto.ListKittensRequest, stream proto.Kittens_ListKittensServer) error {
+	for _, id := range []string{"1", "2"} {
+		if err := stream.Send(s.kittens[id]); err != nil {
+			return err
+		}
+	}
+	if s.failList {
+		return status.Error(codes.Unavailable, "store is down")
+	}
+
+	return nil
+}
+
+func (s *fakeServer) WatchKittens(stream proto.Kittens_WatchKittensServer) error {
+	request, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if request.Name == "" {
+		return status.Error(codes.InvalidArgument, "name is required")
+	}
+	if err := stream.SendHeader(metadata.Pairs("watch", "started")); err != nil {
+		return err
+	}
+
+	<-stream.Context().Done()
+	return nil
+}
+
+func setupGateway(t *testing.T) (*httptest.Server, *fakeServer) {
+	server := &fakeServer{kittens: map[string]*proto.Kitten{
+		"1": {Id: "1", Name: "Felix", Weight: 12.3},
+		"2": {Id: "2", Name: "Fat Freddy's Cat", Weight: 20},
+	}}
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	proto.RegisterKittensServer(s, server)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	hs := httptest.NewServer(New(proto.NewKittensClient(conn)))
+	t.Cleanup(hs.Close)
+
+	return hs, server
+}
+
+func do(t *testing.T, method, url, body string) (*http.Response, string) {
+	r, _ := http.NewRequest(method, url, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer kittens")
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, _ := ioutil.ReadAll(resp.Body)
+
+	return resp, string(b)
+}
+
+func TestForwardsAuthorizationHeader(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	resp, body := do(t, http.MethodPost, hs.URL+"/v1/hello", `{"name": "Nic"}`)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.JSONEq(t, `{"msg": "Hello Nic Bearer kittens"}`, body)
+}
+
+func TestGetsKitten(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	resp, body := do(t, http.MethodGet, hs.URL+"/v1/kittens/1", "")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"id": "1", "name": "Felix", "weight": 12.3}`, body)
+}
+
+func TestCreatesKitten(t *testing.T) {
+	hs, server := setupGateway(t)
+
+	resp, _ := do(t, http.MethodPost, hs.URL+"/v1/kittens", `{"id": "3", "name": "Garfield", "weight": 9}`)
+
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "Garfield", server.kittens["3"].Name)
+}
+
+func TestMapsStatusCodes(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	resp, body := do(t, http.MethodGet, hs.URL+"/v1/kittens/99", "")
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.JSONEq(t, `{"code": 5, "status": "NotFound", "message": "kitten 99 not found"}`, body)
+
+	resp, _ = do(t, http.MethodPost, hs.URL+"/v1/kittens", `{"id": "1"}`)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+
+	resp, _ = do(t, http.MethodPost, hs.URL+"/v1/kittens", `{"id": `)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp, _ = do(t, http.MethodGet, hs.URL+"/v1/search?name=Felix", "")
+	assert.Equal(t, http.StatusNotImplemented, resp.StatusCode)
+}
+
+func TestRejectsWrongMethod(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	resp, _ := do(t, http.MethodPatch, hs.URL+"/v1/kittens/1", "")
+
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	assert.Equal(t, "GET, PUT, DELETE", resp.Header.Get("Allow"))
+}
+
+func TestStreamsKittensAsNDJSON(t *testing.T) {
+	hs, server := setupGateway(t)
+	server.failList = true
+
+	resp, body := do(t, http.MethodGet, hs.URL+"/v1/kittens", "")
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+
+	lines := []map[string]interface{}{}
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, "Felix", lines[0]["name"])
+	assert.Equal(t, "Fat Freddy's Cat", lines[1]["name"])
+	assert.Equal(t, "Unavailable", lines[2]["error"].(map[string]interface{})["status"])
+}
+
+func TestWatchSendsHeadersBeforeFirstEvent(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, hs.URL+"/v1/watch?name=Felix", nil)
+
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+}
+
+func TestWatchReturnsErrorWhenServerRejectsStream(t *testing.T) {
+	hs, _ := setupGateway(t)
+
+	resp, err := http.Get(hs.URL + "/v1/watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+}
+
+func TestHTTPStatus(t *testing.T) {
+	assert.Equal(t, 499, HTTPStatus(codes.Canceled))
+	assert.Equal(t, http.StatusGatewayTimeout, HTTPStatus(codes.DeadlineExceeded))
+	assert.Equal(t, http.StatusUnauthorized, HTTPStatus(codes.Unauthenticated))
+	assert.Equal(t, http.StatusTooManyRequests, HTTPStatus(codes.ResourceExhausted))
+	assert.Equal(t, http.StatusInternalServerError, HTTPStatus(codes.DataLoss))
+}
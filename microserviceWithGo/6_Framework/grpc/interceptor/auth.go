@@ -0,0 +1,91 @@
+package interceptor
+
+import (
+	"context"
+	"crypto/subtle"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenAuth checks the bearer token in the authorization metadata of every
+// call, calls without a valid token fail with codes.Unauthenticated
+type TokenAuth struct {
+	// Valid reports whether a token is accepted.
+	Valid func(token string) bool
+	// Skip lists full method names which do not need a token, e.g. health
+	// checks.
+	Skip []string
+}
+
+// StaticToken accepts only the given token
+func StaticToken(token string) func(string) bool {
+	return func(t string) bool {
+		return subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1
+	}
+}
+
+// UnaryServerInterceptor checks the token of unary calls
+func (a *TokenAuth) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor checks the token when a stream is opened
+func (a *TokenAuth) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func (a *TokenAuth) authorize(ctx context.Context, method string) error {
+	for _, m := range a.Skip {
+		if m == method {
+			return nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return status.Error(codes.Unauthenticated, "missing authorization token")
+	}
+
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	if a.Valid == nil || !a.Valid(token) {
+		return status.Error(codes.Unauthenticated, "invalid authorization token")
+	}
+
+	return nil
+}
+
+// TokenCredentials sends a bearer token with every call, use it with
+// grpc.WithPerRPCCredentials
+type TokenCredentials struct {
+	Token string
+	// AllowInsecure allows the token to be sent without transport security,
+	// only use it for local development.
+	AllowInsecure bool
+}
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t TokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + t.Token}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+func (t TokenCredentials) RequireTransportSecurity() bool {
+	return !t.AllowInsecure
+}
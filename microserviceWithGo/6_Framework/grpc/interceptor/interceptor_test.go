@@ -0,0 +1,191 @@
+package interceptor
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// testServer panics for the kitten "panic" and blocks for the kitten "slow"
+type testServer struct {
+	proto.UnimplementedKittensServer
+}
+
+func (s *testServer) Get(ctx context.Context, request *proto.GetRequest) (*proto.Kitten, error) {
+	switch request.Id {
+	case "panic":
+		panic("boom")
+	case "slow":
+		<-ctx.Done()
+		return nil, status.FromContextError(ctx.Err()).Err()
+	}
+
+	return &proto.Kitten{Id: request.Id}, nil
+}
+
+func (s *testServer) ListKittens(request *proto.ListKittensRequest, stream proto.Kittens_ListKittensServer) error {
+	if request.Name == "panic" {
+		panic("boom")
+	}
+
+	return stream.Send(&proto.Kitten{Id: "1"})
+}
+
+func setup(t *testing.T, server []grpc.ServerOption, client ...grpc.DialOption) proto.KittensClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(server...)
+	proto.RegisterKittensServer(s, &testServer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	client = append(client,
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	conn, err := grpc.Dial("bufnet", client...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return proto.NewKittensClient(conn)
+}
+
+func TestLoggerLogsMethodAndCode(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := log.New(out, "", 0)
+	client := setup(t, []grpc.ServerOption{grpc.UnaryInterceptor(UnaryServerLogger(logger))})
+
+	client.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	client.Hello(context.Background(), &proto.Request{})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.Contains(t, lines[0], "grpc.method=/bmigo.grpc.Kittens/Get grpc.code=OK")
+	assert.Contains(t, lines[1], "grpc.code=Unimplemented")
+	assert.Contains(t, lines[1], "grpc.error=")
+}
+
+func TestRecoveryTurnsPanicIntoInternal(t *testing.T) {
+	logger := log.New(ioutil.Discard, "", 0)
+	client := setup(t, []grpc.ServerOption{
+		grpc.UnaryInterceptor(UnaryServerRecovery(logger)),
+		grpc.StreamInterceptor(StreamServerRecovery(logger)),
+	})
+
+	_, err := client.Get(context.Background(), &proto.GetRequest{Id: "panic"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	stream, _ := client.ListKittens(context.Background(), &proto.ListKittensRequest{Name: "panic"})
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Internal, status.Code(err))
+
+	// the server is still serving
+	_, err = client.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	assert.Nil(t, err)
+}
+
+func TestMetricsCountCallsByCode(t *testing.T) {
+	metrics := NewMetrics()
+	client := setup(t, []grpc.ServerOption{
+		grpc.UnaryInterceptor(metrics.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(metrics.StreamServerInterceptor()),
+	})
+
+	client.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	client.Get(context.Background(), &proto.GetRequest{Id: "2"})
+	client.Hello(context.Background(), &proto.Request{})
+	stream, _ := client.ListKittens(context.Background(), &proto.ListKittensRequest{})
+	for _, err := stream.Recv(); err == nil; _, err = stream.Recv() {
+	}
+
+	snapshot := metrics.Snapshot()
+	assert.Equal(t, 3, len(snapshot))
+	assert.Equal(t, "/bmigo.grpc.Kittens/Get", snapshot[0].Method)
+	assert.Equal(t, uint64(2), snapshot[0].Requests)
+	assert.Equal(t, uint64(2), snapshot[0].Codes[codes.OK])
+	assert.Equal(t, uint64(1), snapshot[1].Codes[codes.Unimplemented])
+	assert.Equal(t, "/bmigo.grpc.Kittens/ListKittens", snapshot[2].Method)
+	assert.Equal(t, 0, snapshot[0].InFlight)
+}
+
+func TestTokenAuthRejectsCallsWithoutValidToken(t *testing.T) {
+	auth := &TokenAuth{Valid: StaticToken("secret"), Skip: []string{"/bmigo.grpc.Kittens/Hello"}}
+	server := []grpc.ServerOption{
+		grpc.UnaryInterceptor(auth.UnaryServerInterceptor()),
+		grpc.StreamInterceptor(auth.StreamServerInterceptor()),
+	}
+
+	anonymous := setup(t, server)
+	_, err := anonymous.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = anonymous.Hello(context.Background(), &proto.Request{})
+	assert.Equal(t, codes.Unimplemented, status.Code(err))
+
+	wrong := setup(t, server, grpc.WithPerRPCCredentials(TokenCredentials{Token: "guess", AllowInsecure: true}))
+	stream, _ := wrong.ListKittens(context.Background(), &proto.ListKittensRequest{})
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	valid := setup(t, server, grpc.WithPerRPCCredentials(TokenCredentials{Token: "secret", AllowInsecure: true}))
+	_, err = valid.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	assert.Nil(t, err)
+}
+
+func TestTokenCredentialsRequireTransportSecurity(t *testing.T) {
+	lis := bufconn.Listen(1024)
+	defer lis.Close()
+
+	_, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+		grpc.WithPerRPCCredentials(TokenCredentials{Token: "secret"}),
+	)
+
+	assert.NotNil(t, err)
+}
+
+func TestClientDeadlineLimitsCallsWithoutDeadline(t *testing.T) {
+	client := setup(t, nil, grpc.WithUnaryInterceptor(UnaryClientDeadline(20*time.Millisecond)))
+
+	start := time.Now()
+	_, err := client.Get(context.Background(), &proto.GetRequest{Id: "slow"})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestClientDeadlineKeepsExistingDeadline(t *testing.T) {
+	client := setup(t, nil, grpc.WithUnaryInterceptor(UnaryClientDeadline(time.Nanosecond)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := client.Get(ctx, &proto.GetRequest{Id: "1"})
+
+	assert.Nil(t, err)
+}
+
+func TestStreamClientDeadlineEndsStream(t *testing.T) {
+	client := setup(t, nil, grpc.WithStreamInterceptor(StreamClientDeadline(time.Second)))
+
+	stream, err := client.ListKittens(context.Background(), &proto.ListKittensRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,52 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerLogger logs every call as key=value pairs with the method,
+// status code, duration and address of the caller
+func UnaryServerLogger(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(logger, ctx, "unary", info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// StreamServerLogger logs every stream once it has finished, see
+// UnaryServerLogger
+func StreamServerLogger(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(logger, ss.Context(), "stream", info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+func logCall(logger *log.Logger, ctx context.Context, kind, method string, start time.Time, err error) {
+	addr := "unknown"
+	if p, ok := peer.FromContext(ctx); ok {
+		addr = p.Addr.String()
+	}
+
+	s := status.Convert(err)
+	if err == nil {
+		logger.Printf("grpc.kind=%v grpc.method=%v grpc.code=%v grpc.duration=%v peer.address=%v",
+			kind, method, s.Code(), time.Since(start), addr)
+		return
+	}
+
+	logger.Printf("grpc.kind=%v grpc.method=%v grpc.code=%v grpc.duration=%v peer.address=%v grpc.error=%q",
+		kind, method, s.Code(), time.Since(start), addr, s.Message())
+}
@@ -0,0 +1,108 @@
+package interceptor
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodMetrics describe the calls to one method
+type MethodMetrics struct {
+	Method   string
+	Requests uint64
+	// Codes counts the calls by status code, including codes.OK
+	Codes map[codes.Code]uint64
+	// Duration is the total time spent in the method, divide by Requests
+	// for the average
+	Duration    time.Duration
+	MaxDuration time.Duration
+	// InFlight is the number of calls which have not finished yet
+	InFlight int
+}
+
+// Metrics records the number, outcome and duration of calls per method
+type Metrics struct {
+	mutex   sync.Mutex
+	methods map[string]*MethodMetrics
+}
+
+// NewMetrics creates a new instance of Metrics
+func NewMetrics() *Metrics {
+	return &Metrics{methods: map[string]*MethodMetrics{}}
+}
+
+// UnaryServerInterceptor records unary calls
+func (m *Metrics) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		done := m.start(info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor records streams, the duration is the lifetime of
+// the stream
+func (m *Metrics) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		done := m.start(info.FullMethod)
+		err := handler(srv, ss)
+		done(err)
+
+		return err
+	}
+}
+
+// Snapshot returns a copy of the metrics of every method sorted by name
+func (m *Metrics) Snapshot() []MethodMetrics {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	snapshot := make([]MethodMetrics, 0, len(m.methods))
+	for _, mm := range m.methods {
+		c := *mm
+		c.Codes = make(map[codes.Code]uint64, len(mm.Codes))
+		for code, n := range mm.Codes {
+			c.Codes[code] = n
+		}
+		snapshot = append(snapshot, c)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool { return snapshot[i].Method < snapshot[j].Method })
+
+	return snapshot
+}
+
+func (m *Metrics) start(method string) func(err error) {
+	start := time.Now()
+
+	m.mutex.Lock()
+	mm, ok := m.methods[method]
+	if !ok {
+		mm = &MethodMetrics{Method: method, Codes: map[codes.Code]uint64{}}
+		m.methods[method] = mm
+	}
+	mm.InFlight++
+	m.mutex.Unlock()
+
+	return func(err error) {
+		d := time.Since(start)
+
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		mm.InFlight--
+		mm.Requests++
+		mm.Codes[status.Code(err)]++
+		mm.Duration += d
+		if d > mm.MaxDuration {
+			mm.MaxDuration = d
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package interceptor
+
+import (
+	"context"
+	"log"
+	"runtime/debug"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerRecovery turns a panic in a handler into a codes.Internal
+// error instead of crashing the server, the stack is written to logger
+func UnaryServerRecovery(logger *log.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerRecovery turns a panic in a stream handler into a
+// codes.Internal error, see UnaryServerRecovery
+func StreamServerRecovery(logger *log.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if p := recover(); p != nil {
+				err = recovered(logger, info.FullMethod, p)
+			}
+		}()
+
+		return handler(srv, ss)
+	}
+}
+
+// recovered logs the panic, the details are not sent to the client
+func recovered(logger *log.Logger, method string, p interface{}) error {
+	logger.Printf("grpc.method=%v panic=%q\n%s", method, p, debug.Stack())
+
+	return status.Error(codes.Internal, "internal error")
+}
@@ -0,0 +1,74 @@
+package kittenerr
+
+import (
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+// Decode turns the status returned by a Kittens RPC into the typed error it
+// was sent for. Errors without an ErrorInfo of the service, e.g. deadlines or
+// connection failures, are returned unchanged.
+func Decode(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var (
+		info         *errdetails.ErrorInfo
+		badRequest   *errdetails.BadRequest
+		resource     *errdetails.ResourceInfo
+		precondition *errdetails.PreconditionFailure
+		retry        *errdetails.RetryInfo
+	)
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *errdetails.ErrorInfo:
+			info = d
+		case *errdetails.BadRequest:
+			badRequest = d
+		case *errdetails.ResourceInfo:
+			resource = d
+		case *errdetails.PreconditionFailure:
+			precondition = d
+		case *errdetails.RetryInfo:
+			retry = d
+		}
+	}
+
+	if info == nil || info.Domain != Domain {
+		return err
+	}
+
+	switch info.Reason {
+	case ReasonInvalidArgument:
+		e := &ValidationError{}
+		for _, v := range badRequest.GetFieldViolations() {
+			e.Violations = append(e.Violations, FieldViolation{Field: v.Field, Description: v.Description})
+		}
+		return e
+	case ReasonNotFound:
+		return &NotFoundError{Type: resource.GetResourceType(), Name: resource.GetResourceName()}
+	case ReasonAlreadyExists:
+		return &ExistsError{Type: resource.GetResourceType(), Name: resource.GetResourceName()}
+	case ReasonFailedPrecondition:
+		e := &PreconditionError{}
+		for _, v := range precondition.GetViolations() {
+			e.Violations = append(e.Violations, PreconditionViolation{Type: v.Type, Subject: v.Subject, Description: v.Description})
+		}
+		return e
+	case ReasonUnavailable:
+		e := &UnavailableError{Message: s.Message()}
+		if d, err := ptypes.Duration(retry.GetRetryDelay()); err == nil {
+			e.RetryAfter = d
+		}
+		return e
+	default:
+		return err
+	}
+}
@@ -0,0 +1,178 @@
+// Package kittenerr defines the errors of the Kittens service. Every error
+// is sent as a google.rpc.Status with an ErrorInfo naming its reason and the
+// details a client needs to handle it, e.g. the invalid fields or when to
+// retry.
+//
+// The server returns the typed errors from its RPCs, gRPC sends them with
+// their GRPCStatus. Clients turn the status back into the typed error with
+// Decode:
+//
+//	_, err := client.Get(ctx, &proto.GetRequest{Id: "7"})
+//	var notFound *kittenerr.NotFoundError
+//	if errors.As(kittenerr.Decode(err), &notFound) {
+//		...
+//	}
+package kittenerr
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain of the Kittens service
+const Domain = "bmigo.grpc.Kittens"
+
+// Reasons of the errors, sent in the ErrorInfo of the status
+const (
+	ReasonInvalidArgument    = "INVALID_ARGUMENT"
+	ReasonNotFound           = "NOT_FOUND"
+	ReasonAlreadyExists      = "ALREADY_EXISTS"
+	ReasonFailedPrecondition = "FAILED_PRECONDITION"
+	ReasonUnavailable        = "UNAVAILABLE"
+)
+
+// FieldViolation describes an invalid field of a request, Field is the path
+// of the field, e.g. kitten.name
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// ValidationError is returned for requests with invalid fields
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+// Invalid returns a ValidationError for a single field
+func Invalid(field, description string) *ValidationError {
+	return &ValidationError{Violations: []FieldViolation{{Field: field, Description: description}}}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		fields[i] = fmt.Sprintf("%v: %v", v.Field, v.Description)
+	}
+
+	return "invalid request: " + strings.Join(fields, ", ")
+}
+
+// GRPCStatus returns the status sent for the error
+func (e *ValidationError) GRPCStatus() *status.Status {
+	br := &errdetails.BadRequest{}
+	for _, v := range e.Violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	return withDetails(codes.InvalidArgument, e.Error(), ReasonInvalidArgument, br)
+}
+
+// NotFoundError is returned when a resource does not exist, Type is the kind
+// of resource, e.g. kitten, and Name its id
+type NotFoundError struct {
+	Type string
+	Name string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%v %v not found", e.Type, e.Name)
+}
+
+// GRPCStatus returns the status sent for the error
+func (e *NotFoundError) GRPCStatus() *status.Status {
+	return withDetails(codes.NotFound, e.Error(), ReasonNotFound,
+		&errdetails.ResourceInfo{ResourceType: e.Type, ResourceName: e.Name})
+}
+
+// ExistsError is returned when a resource to be created already exists
+type ExistsError struct {
+	Type string
+	Name string
+}
+
+func (e *ExistsError) Error() string {
+	return fmt.Sprintf("%v %v already exists", e.Type, e.Name)
+}
+
+// GRPCStatus returns the status sent for the error
+func (e *ExistsError) GRPCStatus() *status.Status {
+	return withDetails(codes.AlreadyExists, e.Error(), ReasonAlreadyExists,
+		&errdetails.ResourceInfo{ResourceType: e.Type, ResourceName: e.Name})
+}
+
+// PreconditionViolation describes a condition the system was not in, Type
+// is the kind of condition, Subject what it applies to
+type PreconditionViolation struct {
+	Type        string
+	Subject     string
+	Description string
+}
+
+// PreconditionError is returned when a valid request can not be served in
+// the current state, the client should not retry it until the state changed
+type PreconditionError struct {
+	Violations []PreconditionViolation
+}
+
+func (e *PreconditionError) Error() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		descriptions[i] = v.Description
+	}
+
+	return "failed precondition: " + strings.Join(descriptions, ", ")
+}
+
+// GRPCStatus returns the status sent for the error
+func (e *PreconditionError) GRPCStatus() *status.Status {
+	pf := &errdetails.PreconditionFailure{}
+	for _, v := range e.Violations {
+		pf.Violations = append(pf.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        v.Type,
+			Subject:     v.Subject,
+			Description: v.Description,
+		})
+	}
+
+	return withDetails(codes.FailedPrecondition, e.Error(), ReasonFailedPrecondition, pf)
+}
+
+// UnavailableError is returned when the service can not serve requests for
+// a while, the client may retry after RetryAfter
+type UnavailableError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *UnavailableError) Error() string {
+	return fmt.Sprintf("unavailable: %v, retry after %v", e.Message, e.RetryAfter)
+}
+
+// GRPCStatus returns the status sent for the error, its message is Message
+// as the retry delay is sent in the details
+func (e *UnavailableError) GRPCStatus() *status.Status {
+	return withDetails(codes.Unavailable, e.Message, ReasonUnavailable,
+		&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(e.RetryAfter)})
+}
+
+func withDetails(code codes.Code, msg, reason string, detail proto.Message) *status.Status {
+	s := status.New(code, msg)
+
+	d, err := s.WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: Domain}, detail)
+	if err != nil {
+		// the details are plain messages, marshalling them can not fail
+		return s
+	}
+
+	return d
+}
@@ -0,0 +1,61 @@
+package kittenerr
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// send returns the error a client receives for err, the status goes through
+// its protobuf form like on the wire
+func send(err error) error {
+	return status.FromProto(status.Convert(err).Proto()).Err()
+}
+
+func TestDecodesTypedErrors(t *testing.T) {
+	errs := []error{
+		&ValidationError{Violations: []FieldViolation{
+			{Field: "kitten.name", Description: "must not be empty"},
+			{Field: "kitten.weight", Description: "must not be negative"},
+		}},
+		&NotFoundError{Type: "kitten", Name: "7"},
+		&ExistsError{Type: "kitten", Name: "1"},
+		&PreconditionError{Violations: []PreconditionViolation{{Type: "STATE", Subject: "kitten/1", Description: "kitten is being adopted"}}},
+		&UnavailableError{Message: "kitten store unavailable", RetryAfter: 1500 * time.Millisecond},
+	}
+
+	for _, err := range errs {
+		assert.Equal(t, err, Decode(send(err)))
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	assert.Equal(t, codes.InvalidArgument, status.Code(Invalid("name", "must not be empty")))
+	assert.Equal(t, codes.NotFound, status.Code(&NotFoundError{}))
+	assert.Equal(t, codes.AlreadyExists, status.Code(&ExistsError{}))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(&PreconditionError{}))
+	assert.Equal(t, codes.Unavailable, status.Code(&UnavailableError{}))
+}
+
+func TestDecodeReturnsOtherErrorsUnchanged(t *testing.T) {
+	plain := status.Error(codes.NotFound, "no details")
+	deadline := context.DeadlineExceeded
+	other := errors.New("boom")
+
+	assert.Nil(t, Decode(nil))
+	assert.Equal(t, plain, Decode(plain))
+	assert.Equal(t, deadline, Decode(deadline))
+	assert.Equal(t, other, Decode(other))
+}
+
+func TestErrorsAs(t *testing.T) {
+	var notFound *NotFoundError
+
+	assert.True(t, errors.As(Decode(send(&NotFoundError{Type: "kitten", Name: "7"})), &notFound))
+	assert.Equal(t, "kitten 7 not found", notFound.Error())
+}
@@ -0,0 +1,197 @@
+// Package kittens implements the Kittens gRPC service on top of a
+// data.Store, write RPCs publish change events to WatchKittens subscribers.
+package kittens
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittenerr"
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// storeRetryAfter 저장소에 연결할 수 없을 때 클라이언트에게 알려주는 재시도 간격
+const storeRetryAfter = time.Second
+
+type kittenServer struct {
+	store  data.Store // gRPC 와 HTTP 핸들러가 같은 저장소를 공유
+	events *hub       // 쓰기 RPC 의 변경 이벤트를 WatchKittens 구독자에게 전달
+	writes sync.Mutex // if-match 검사와 수정이 다른 쓰기와 섞이지 않도록 쓰기 RPC 를 직렬화
+}
+
+// New returns the Kittens service serving the kittens of store
+func New(store data.Store) proto.KittensServer {
+	return newKittenServer(store)
+}
+
+func newKittenServer(store data.Store) *kittenServer {
+	return &kittenServer{store: store, events: newHub()}
+}
+
+func (k *kittenServer) Hello(ctx context.Context, request *proto.Request) (*proto.Response, error) {
+	if request.Name == "" {
+		return nil, kittenerr.Invalid("name", "must not be empty")
+	}
+
+	response := &proto.Response{} // 응답 객체 생성
+	response.Msg = fmt.Sprintf("Hello %v", request.Name)
+
+	return response, nil
+}
+
+func (k *kittenServer) Search(ctx context.Context, request *proto.SearchRequest) (*proto.SearchResponse, error) {
+	if request.Name == "" {
+		return nil, kittenerr.Invalid("name", "must not be empty")
+	}
+
+	response := &proto.SearchResponse{}
+	for _, kitten := range k.store.Search(request.Name) {
+		response.Kittens = append(response.Kittens, toProto(kitten))
+	}
+
+	return response, nil
+}
+
+func (k *kittenServer) Get(ctx context.Context, request *proto.GetRequest) (*proto.Kitten, error) {
+	kitten, err := k.store.Get(request.Id)
+	if err != nil {
+		return nil, k.toStatus(err, request.Id)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("etag", etag(kitten)))
+
+	return toProto(kitten), nil
+}
+
+func (k *kittenServer) Create(ctx context.Context, request *proto.CreateRequest) (*proto.Kitten, error) {
+	if err := validate(request.Kitten, false); err != nil {
+		return nil, err
+	}
+
+	k.writes.Lock()
+	defer k.writes.Unlock()
+
+	kitten, err := k.store.Create(fromProto(request.Kitten))
+	if err != nil {
+		return nil, k.toStatus(err, request.Kitten.Id)
+	}
+	k.publish(proto.KittenEvent_CREATED, kitten)
+
+	return toProto(kitten), nil
+}
+
+func (k *kittenServer) Update(ctx context.Context, request *proto.UpdateRequest) (*proto.Kitten, error) {
+	if err := validate(request.Kitten, true); err != nil {
+		return nil, err
+	}
+
+	k.writes.Lock()
+	defer k.writes.Unlock()
+
+	// if-match 메타데이터가 있으면 Get 에서 받은 etag 와 현재 고양이가 같을 때만 수정
+	md, _ := metadata.FromIncomingContext(ctx)
+	if match := md.Get("if-match"); len(match) > 0 {
+		current, err := k.store.Get(request.Kitten.Id)
+		if err != nil {
+			return nil, k.toStatus(err, request.Kitten.Id)
+		}
+		if match[0] != etag(current) {
+			return nil, &kittenerr.PreconditionError{Violations: []kittenerr.PreconditionViolation{{
+				Type:        "ETAG",
+				Subject:     "kitten/" + request.Kitten.Id,
+				Description: "kitten has been modified",
+			}}}
+		}
+	}
+
+	kitten, err := k.store.Update(fromProto(request.Kitten))
+	if err != nil {
+		return nil, k.toStatus(err, request.Kitten.Id)
+	}
+	grpc.SetHeader(ctx, metadata.Pairs("etag", etag(kitten)))
+	k.publish(proto.KittenEvent_UPDATED, kitten)
+
+	return toProto(kitten), nil
+}
+
+func (k *kittenServer) Delete(ctx context.Context, request *proto.DeleteRequest) (*proto.DeleteResponse, error) {
+	k.writes.Lock()
+	defer k.writes.Unlock()
+
+	kitten, err := k.store.Delete(request.Id)
+	if err != nil {
+		return nil, k.toStatus(err, request.Id)
+	}
+	k.publish(proto.KittenEvent_DELETED, kitten)
+
+	return &proto.DeleteResponse{}, nil
+}
+
+func (k *kittenServer) publish(t proto.KittenEvent_Type, kitten data.Kitten) {
+	k.events.publish(&proto.KittenEvent{Type: t, Kitten: toProto(kitten)})
+}
+
+// etag 고양이의 현재 상태를 나타내는 값, 수정되면 바뀜
+func etag(k data.Kitten) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v/%v/%v", k.Id, k.Name, k.Weight)
+
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
+func toProto(k data.Kitten) *proto.Kitten {
+	return &proto.Kitten{Id: k.Id, Name: k.Name, Weight: k.Weight}
+}
+
+func fromProto(k *proto.Kitten) data.Kitten {
+	return data.Kitten{Id: k.Id, Name: k.Name, Weight: k.Weight}
+}
+
+// validate 요청의 고양이 필드를 검사하고 잘못된 필드를 모두 반환
+func validate(kitten *proto.Kitten, needID bool) error {
+	if kitten == nil {
+		return kittenerr.Invalid("kitten", "is required")
+	}
+
+	e := &kittenerr.ValidationError{}
+	if needID && kitten.Id == "" {
+		e.Violations = append(e.Violations, kittenerr.FieldViolation{Field: "kitten.id", Description: "must not be empty"})
+	}
+	if kitten.Name == "" {
+		e.Violations = append(e.Violations, kittenerr.FieldViolation{Field: "kitten.name", Description: "must not be empty"})
+	}
+	if kitten.Weight < 0 {
+		e.Violations = append(e.Violations, kittenerr.FieldViolation{Field: "kitten.weight", Description: "must not be negative"})
+	}
+
+	if len(e.Violations) > 0 {
+		return e
+	}
+
+	return nil
+}
+
+// toStatus 저장소 에러를 상세 정보가 담긴 gRPC 에러로 변환
+func (k *kittenServer) toStatus(err error, id string) error {
+	switch err {
+	case data.ErrNotFound:
+		return &kittenerr.NotFoundError{Type: "kitten", Name: id}
+	case data.ErrExists:
+		return &kittenerr.ExistsError{Type: "kitten", Name: id}
+	}
+
+	// 저장소에 연결할 수 없으면 잠시 후 재시도하도록 안내
+	if p, ok := k.store.(data.Pinger); ok && p.Ping() != nil {
+		return &kittenerr.UnavailableError{Message: "kitten store unavailable", RetryAfter: storeRetryAfter}
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,172 @@
+package kittens
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittenerr"
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// setupClient starts the server on an in-memory listener
+func setupClient(t *testing.T) proto.KittensClient {
+	client, _ := setupServer(t)
+
+	return client
+}
+
+func setupServer(t *testing.T) (proto.KittensClient, *kittenServer) {
+	return setupStore(t, &data.MemoryStore{})
+}
+
+func setupStore(t *testing.T, store data.Store) (proto.KittensClient, *kittenServer) {
+	server := newKittenServer(store)
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	proto.RegisterKittensServer(s, server)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return proto.NewKittensClient(conn), server
+}
+
+func TestSearchReturnsMatchingKittens(t *testing.T) {
+	client := setupClient(t)
+
+	response, err := client.Search(context.Background(), &proto.SearchRequest{Name: "Garfield"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(response.Kittens))
+	assert.Equal(t, "3", response.Kittens[0].Id)
+}
+
+func TestSearchWithoutNameIsInvalid(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Search(context.Background(), &proto.SearchRequest{})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCreateGetUpdateDelete(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	created, err := client.Create(ctx, &proto.CreateRequest{Kitten: &proto.Kitten{Name: "Tom", Weight: 4}})
+	assert.Nil(t, err)
+	assert.NotEmpty(t, created.Id)
+
+	_, err = client.Update(ctx, &proto.UpdateRequest{Kitten: &proto.Kitten{Id: created.Id, Name: "Tom", Weight: 5}})
+	assert.Nil(t, err)
+
+	kitten, err := client.Get(ctx, &proto.GetRequest{Id: created.Id})
+	assert.Nil(t, err)
+	assert.Equal(t, float32(5), kitten.Weight)
+
+	_, err = client.Delete(ctx, &proto.DeleteRequest{Id: created.Id})
+	assert.Nil(t, err)
+
+	_, err = client.Get(ctx, &proto.GetRequest{Id: created.Id})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestCreateWithTakenIdAlreadyExists(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Create(context.Background(), &proto.CreateRequest{Kitten: &proto.Kitten{Id: "1", Name: "Tom"}})
+
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+}
+
+// downStore is a store whose database can not be reached
+type downStore struct {
+	data.MemoryStore
+}
+
+func (s *downStore) Get(id string) (data.Kitten, error) {
+	return data.Kitten{}, errors.New("no reachable servers")
+}
+
+func (s *downStore) Ping() error {
+	return errors.New("no reachable servers")
+}
+
+func TestHelloWithoutNameIsInvalid(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Hello(context.Background(), &proto.Request{})
+
+	assert.Equal(t, kittenerr.Invalid("name", "must not be empty"), kittenerr.Decode(err))
+}
+
+func TestCreateReportsEveryInvalidField(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Create(context.Background(), &proto.CreateRequest{Kitten: &proto.Kitten{Weight: -1}})
+
+	assert.Equal(t, &kittenerr.ValidationError{Violations: []kittenerr.FieldViolation{
+		{Field: "kitten.name", Description: "must not be empty"},
+		{Field: "kitten.weight", Description: "must not be negative"},
+	}}, kittenerr.Decode(err))
+}
+
+func TestGetUnknownKittenIsNotFound(t *testing.T) {
+	client := setupClient(t)
+
+	_, err := client.Get(context.Background(), &proto.GetRequest{Id: "99"})
+
+	assert.Equal(t, &kittenerr.NotFoundError{Type: "kitten", Name: "99"}, kittenerr.Decode(err))
+}
+
+func TestUnreachableStoreIsRetryable(t *testing.T) {
+	client, _ := setupStore(t, &downStore{})
+
+	_, err := client.Get(context.Background(), &proto.GetRequest{Id: "1"})
+
+	assert.Equal(t, &kittenerr.UnavailableError{Message: "kitten store unavailable", RetryAfter: storeRetryAfter}, kittenerr.Decode(err))
+}
+
+func TestUpdateFailsPreconditionWhenKittenWasModified(t *testing.T) {
+	client := setupClient(t)
+	ctx := context.Background()
+
+	var header metadata.MD
+	_, err := client.Get(ctx, &proto.GetRequest{Id: "1"}, grpc.Header(&header))
+	assert.Nil(t, err)
+	etag := header.Get("etag")[0]
+
+	_, err = client.Update(ctx, &proto.UpdateRequest{Kitten: &proto.Kitten{Id: "1", Name: "Felix", Weight: 13}})
+	assert.Nil(t, err)
+
+	ctx = metadata.AppendToOutgoingContext(ctx, "if-match", etag)
+	_, err = client.Update(ctx, &proto.UpdateRequest{Kitten: &proto.Kitten{Id: "1", Name: "Felix", Weight: 14}})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, &kittenerr.PreconditionError{Violations: []kittenerr.PreconditionViolation{{
+		Type:        "ETAG",
+		Subject:     "kitten/1",
+		Description: "kitten has been modified",
+	}}}, kittenerr.Decode(err))
+
+	kitten, _ := client.Get(context.Background(), &proto.GetRequest{Id: "1"})
+	assert.Equal(t, float32(13), kitten.Weight)
+}
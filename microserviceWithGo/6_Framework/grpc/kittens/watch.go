@@ -0,0 +1,147 @@
+package kittens
+
+import (
+	"io"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// watchBuffer 구독자별로 쌓아둘 수 있는 이벤트 수, 넘으면 구독을 끊음
+const watchBuffer = 64
+
+// hub 쓰기 RPC 에서 발생한 변경 이벤트를 구독자에게 전달
+type hub struct {
+	mutex       sync.Mutex
+	subscribers map[chan *proto.KittenEvent]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subscribers: map[chan *proto.KittenEvent]struct{}{}}
+}
+
+// subscribe returns a channel of events, the channel is closed when the
+// subscriber falls more than watchBuffer events behind
+func (h *hub) subscribe() (events <-chan *proto.KittenEvent, cancel func()) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	c := make(chan *proto.KittenEvent, watchBuffer)
+	h.subscribers[c] = struct{}{}
+
+	return c, func() {
+		h.mutex.Lock()
+		defer h.mutex.Unlock()
+
+		if _, ok := h.subscribers[c]; ok {
+			delete(h.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+func (h *hub) publish(event *proto.KittenEvent) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	for c := range h.subscribers {
+		select {
+		case c <- event:
+		default:
+			// 느린 구독자 때문에 쓰기 RPC 가 막히지 않도록 구독 해제
+			delete(h.subscribers, c)
+			close(c)
+		}
+	}
+}
+
+func (h *hub) count() int {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	return len(h.subscribers)
+}
+
+// ListKittens 모든 고양이를 하나씩 스트리밍, 클라이언트가 읽지 않으면
+// HTTP/2 흐름 제어 윈도우가 차서 Send 가 대기
+func (k *kittenServer) ListKittens(request *proto.ListKittensRequest, stream proto.Kittens_ListKittensServer) error {
+	for _, kitten := range k.store.List() {
+		if request.Name != "" && kitten.Name != request.Name {
+			continue
+		}
+
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		if err := stream.Send(toProto(kitten)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WatchKittens 클라이언트가 첫 WatchRequest 를 보내면 변경 이벤트 전송 시작,
+// 이후 요청은 이름 필터를 교체하고 클라이언트가 송신을 닫으면 종료
+func (k *kittenServer) WatchKittens(stream proto.Kittens_WatchKittensServer) error {
+	requests := make(chan *proto.WatchRequest)
+	errs := make(chan error, 1)
+
+	go func() {
+		for {
+			request, err := stream.Recv()
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			select {
+			case requests <- request:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	var events <-chan *proto.KittenEvent
+	name := ""
+
+	for {
+		select {
+		case request := <-requests:
+			name = request.Name
+			if events == nil {
+				var cancel func()
+				events, cancel = k.events.subscribe()
+				defer cancel()
+
+				// 구독이 끝난 뒤 헤더를 보내 클라이언트가 감시 시작을 알 수 있게 함,
+				// 헤더가 비어 있으면 거절된 스트림과 구분할 수 없음
+				if err := stream.SendHeader(metadata.Pairs("watch", "started")); err != nil {
+					return err
+				}
+			}
+		case event, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "watcher fell behind")
+			}
+			if name != "" && event.Kitten.Name != name {
+				continue
+			}
+			if err := stream.Send(event); err != nil {
+				return err
+			}
+		case err := <-errs:
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package kittens
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+func TestListKittensStreamsAllKittens(t *testing.T) {
+	client := setupClient(t)
+
+	stream, err := client.ListKittens(context.Background(), &proto.ListKittensRequest{})
+	assert.Nil(t, err)
+
+	ids := []string{}
+	for {
+		kitten, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		ids = append(ids, kitten.Id)
+	}
+
+	assert.Equal(t, []string{"1", "2", "3"}, ids)
+}
+
+func TestListKittensFiltersByName(t *testing.T) {
+	client := setupClient(t)
+
+	stream, _ := client.ListKittens(context.Background(), &proto.ListKittensRequest{Name: "Felix"})
+	kitten, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "1", kitten.Id)
+
+	_, err = stream.Recv()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestListKittensStopsWhenClientCancels(t *testing.T) {
+	client := setupClient(t)
+	// more than fits in the flow control window, so the server has to wait
+	// for the client to read
+	for i := 0; i < 1000; i++ {
+		name := fmt.Sprint(strings.Repeat("Kitten ", 200), i)
+		client.Create(context.Background(), &proto.CreateRequest{Kitten: &proto.Kitten{Name: name}})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, _ := client.ListKittens(ctx, &proto.ListKittensRequest{})
+	_, err := stream.Recv()
+	assert.Nil(t, err)
+	cancel()
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+// watch opens a watch stream and waits until the server has subscribed it
+func watch(t *testing.T, client proto.KittensClient, server *kittenServer, name string) proto.Kittens_WatchKittensClient {
+	stream, err := client.WatchKittens(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, stream.Send(&proto.WatchRequest{Name: name}))
+
+	for i := 0; i < 1000 && server.events.count() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	return stream
+}
+
+func TestWatchKittensReceivesChangesFromWriteRPCs(t *testing.T) {
+	client, server := setupServer(t)
+	stream := watch(t, client, server, "")
+	ctx := context.Background()
+
+	created, _ := client.Create(ctx, &proto.CreateRequest{Kitten: &proto.Kitten{Name: "Tom"}})
+	client.Update(ctx, &proto.UpdateRequest{Kitten: &proto.Kitten{Id: created.Id, Name: "Tom", Weight: 5}})
+	client.Delete(ctx, &proto.DeleteRequest{Id: created.Id})
+
+	types := []proto.KittenEvent_Type{}
+	for i := 0; i < 3; i++ {
+		event, err := stream.Recv()
+		assert.Nil(t, err)
+		assert.Equal(t, created.Id, event.Kitten.Id)
+		types = append(types, event.Type)
+	}
+
+	assert.Equal(t, []proto.KittenEvent_Type{proto.KittenEvent_CREATED, proto.KittenEvent_UPDATED, proto.KittenEvent_DELETED}, types)
+}
+
+func TestWatchKittensFiltersByName(t *testing.T) {
+	client, server := setupServer(t)
+	stream := watch(t, client, server, "Tom")
+
+	client.Create(context.Background(), &proto.CreateRequest{Kitten: &proto.Kitten{Name: "Jerry"}})
+	client.Create(context.Background(), &proto.CreateRequest{Kitten: &proto.Kitten{Name: "Tom"}})
+
+	event, err := stream.Recv()
+	assert.Nil(t, err)
+	assert.Equal(t, "Tom", event.Kitten.Name)
+}
+
+func TestWatchKittensEndsWhenClientClosesSend(t *testing.T) {
+	client, server := setupServer(t)
+	stream := watch(t, client, server, "")
+
+	stream.CloseSend()
+	_, err := stream.Recv()
+
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestHubDropsSubscriberWhichFallsBehind(t *testing.T) {
+	h := newHub()
+	events, cancel := h.subscribe()
+	defer cancel()
+
+	for i := 0; i <= watchBuffer; i++ {
+		h.publish(&proto.KittenEvent{})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	assert.Equal(t, watchBuffer, received)
+	assert.Equal(t, 0, h.count())
+}
@@ -29,6 +29,55 @@ const (
 // of the legacy proto package is being used.
 const _ = proto.ProtoPackageIsVersion4
 
+type KittenEvent_Type int32
+
+const (
+	KittenEvent_CREATED KittenEvent_Type = 0
+	KittenEvent_UPDATED KittenEvent_Type = 1
+	KittenEvent_DELETED KittenEvent_Type = 2
+)
+
+// Enum value maps for KittenEvent_Type.
+var (
+	KittenEvent_Type_name = map[int32]string{
+		0: "CREATED",
+		1: "UPDATED",
+		2: "DELETED",
+	}
+	KittenEvent_Type_value = map[string]int32{
+		"CREATED": 0,
+		"UPDATED": 1,
+		"DELETED": 2,
+	}
+)
+
+func (x KittenEvent_Type) Enum() *KittenEvent_Type {
+	p := new(KittenEvent_Type)
+	*p = x
+	return p
+}
+
+func (x KittenEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (KittenEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_kittens_proto_enumTypes[0].Descriptor()
+}
+
+func (KittenEvent_Type) Type() protoreflect.EnumType {
+	return &file_kittens_proto_enumTypes[0]
+}
+
+func (x KittenEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use KittenEvent_Type.Descriptor instead.
+func (KittenEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{12, 0}
+}
+
 type Request struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -123,168 +172,1211 @@ func (x *Response) GetMsg() string {
 	return ""
 }
 
-var File_kittens_proto protoreflect.FileDescriptor
+type Kitten struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_kittens_proto_rawDesc = []byte{
-	0x0a, 0x0d, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x0a, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x22, 0x1d, 0x0a, 0x07, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1c, 0x0a, 0x08, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x32, 0x3f, 0x0a, 0x07, 0x4b, 0x69, 0x74, 0x74,
-	0x65, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x13, 0x2e, 0x62,
-	0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x14, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	Id     string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name   string  `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Weight float32 `protobuf:"fixed32,3,opt,name=weight,proto3" json:"weight,omitempty"`
 }
 
-var (
-	file_kittens_proto_rawDescOnce sync.Once
-	file_kittens_proto_rawDescData = file_kittens_proto_rawDesc
-)
+func (x *Kitten) Reset() {
+	*x = Kitten{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_kittens_proto_rawDescGZIP() []byte {
-	file_kittens_proto_rawDescOnce.Do(func() {
-		file_kittens_proto_rawDescData = protoimpl.X.CompressGZIP(file_kittens_proto_rawDescData)
-	})
-	return file_kittens_proto_rawDescData
+func (x *Kitten) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_kittens_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
-var file_kittens_proto_goTypes = []interface{}{
-	(*Request)(nil),  // 0: bmigo.grpc.Request
-	(*Response)(nil), // 1: bmigo.grpc.Response
+func (*Kitten) ProtoMessage() {}
+
+func (x *Kitten) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_kittens_proto_depIdxs = []int32{
-	0, // 0: bmigo.grpc.Kittens.Hello:input_type -> bmigo.grpc.Request
-	1, // 1: bmigo.grpc.Kittens.Hello:output_type -> bmigo.grpc.Response
-	1, // [1:2] is the sub-list for method output_type
-	0, // [0:1] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+
+// Deprecated: Use Kitten.ProtoReflect.Descriptor instead.
+func (*Kitten) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{2}
 }
 
-func init() { file_kittens_proto_init() }
-func file_kittens_proto_init() {
-	if File_kittens_proto != nil {
-		return
+func (x *Kitten) GetId() string {
+	if x != nil {
+		return x.Id
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_kittens_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Request); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_kittens_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Response); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return ""
+}
+
+func (x *Kitten) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Kitten) GetWeight() float32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type SearchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
+		return ms
 	}
-	type x struct{}
-	out := protoimpl.TypeBuilder{
-		File: protoimpl.DescBuilder{
-			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_kittens_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   2,
-			NumExtensions: 0,
-			NumServices:   1,
-		},
-		GoTypes:           file_kittens_proto_goTypes,
-		DependencyIndexes: file_kittens_proto_depIdxs,
-		MessageInfos:      file_kittens_proto_msgTypes,
-	}.Build()
-	File_kittens_proto = out.File
-	file_kittens_proto_rawDesc = nil
-	file_kittens_proto_goTypes = nil
-	file_kittens_proto_depIdxs = nil
+	return mi.MessageOf(x)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConnInterface
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{3}
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion6
+func (x *SearchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
 
-// KittensClient is the client API for Kittens service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type KittensClient interface {
-	Hello(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+type SearchResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kittens []*Kitten `protobuf:"bytes,1,rep,name=kittens,proto3" json:"kittens,omitempty"`
 }
 
-type kittensClient struct {
-	cc grpc.ClientConnInterface
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func NewKittensClient(cc grpc.ClientConnInterface) KittensClient {
-	return &kittensClient{cc}
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (c *kittensClient) Hello(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
-	out := new(Response)
-	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Hello", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return out, nil
+	return mi.MessageOf(x)
 }
 
-// KittensServer is the server API for Kittens service.
-type KittensServer interface {
-	Hello(context.Context, *Request) (*Response, error)
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{4}
 }
 
-// UnimplementedKittensServer can be embedded to have forward compatible implementations.
-type UnimplementedKittensServer struct {
+func (x *SearchResponse) GetKittens() []*Kitten {
+	if x != nil {
+		return x.Kittens
+	}
+	return nil
 }
 
-func (*UnimplementedKittensServer) Hello(context.Context, *Request) (*Response, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+type GetRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 }
 
-func RegisterKittensServer(s *grpc.Server, srv KittensServer) {
-	s.RegisterService(&_Kittens_serviceDesc, srv)
+func (x *GetRequest) Reset() {
+	*x = GetRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
 }
 
-func _Kittens_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Request)
-	if err := dec(in); err != nil {
-		return nil, err
+func (x *GetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRequest) ProtoMessage() {}
+
+func (x *GetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	if interceptor == nil {
-		return srv.(KittensServer).Hello(ctx, in)
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRequest.ProtoReflect.Descriptor instead.
+func (*GetRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetRequest) GetId() string {
+	if x != nil {
+		return x.Id
 	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/bmigo.grpc.Kittens/Hello",
+	return ""
+}
+
+type CreateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kitten *Kitten `protobuf:"bytes,1,opt,name=kitten,proto3" json:"kitten,omitempty"`
+}
+
+func (x *CreateRequest) Reset() {
+	*x = CreateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(KittensServer).Hello(ctx, req.(*Request))
+}
+
+func (x *CreateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateRequest) ProtoMessage() {}
+
+func (x *CreateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return interceptor(ctx, in, info, handler)
+	return mi.MessageOf(x)
 }
 
-var _Kittens_serviceDesc = grpc.ServiceDesc{
+// Deprecated: Use CreateRequest.ProtoReflect.Descriptor instead.
+func (*CreateRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateRequest) GetKitten() *Kitten {
+	if x != nil {
+		return x.Kitten
+	}
+	return nil
+}
+
+type UpdateRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Kitten *Kitten `protobuf:"bytes,1,opt,name=kitten,proto3" json:"kitten,omitempty"`
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *UpdateRequest) GetKitten() *Kitten {
+	if x != nil {
+		return x.Kitten
+	}
+	return nil
+}
+
+type DeleteRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (x *DeleteRequest) Reset() {
+	*x = DeleteRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRequest) ProtoMessage() {}
+
+func (x *DeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DeleteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{9}
+}
+
+type ListKittensRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *ListKittensRequest) Reset() {
+	*x = ListKittensRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ListKittensRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListKittensRequest) ProtoMessage() {}
+
+func (x *ListKittensRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListKittensRequest.ProtoReflect.Descriptor instead.
+func (*ListKittensRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListKittensRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *WatchRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type KittenEvent struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type   KittenEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=bmigo.grpc.KittenEvent_Type" json:"type,omitempty"`
+	Kitten *Kitten          `protobuf:"bytes,2,opt,name=kitten,proto3" json:"kitten,omitempty"`
+}
+
+func (x *KittenEvent) Reset() {
+	*x = KittenEvent{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_kittens_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *KittenEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KittenEvent) ProtoMessage() {}
+
+func (x *KittenEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_kittens_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KittenEvent.ProtoReflect.Descriptor instead.
+func (*KittenEvent) Descriptor() ([]byte, []int) {
+	return file_kittens_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *KittenEvent) GetType() KittenEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return KittenEvent_CREATED
+}
+
+func (x *KittenEvent) GetKitten() *Kitten {
+	if x != nil {
+		return x.Kitten
+	}
+	return nil
+}
+
+var File_kittens_proto protoreflect.FileDescriptor
+
+var file_kittens_proto_rawDesc = []byte{
+	0x0a, 0x0d, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0a, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x22, 0x1d, 0x0a, 0x07, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x1c, 0x0a, 0x08, 0x52, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x73, 0x67, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x03, 0x6d, 0x73, 0x67, 0x22, 0x44, 0x0a, 0x06, 0x4b, 0x69, 0x74, 0x74,
+	0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x77, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x23,
+	0x0a, 0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x22, 0x3e, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67,
+	0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x52, 0x07, 0x6b, 0x69, 0x74, 0x74,
+	0x65, 0x6e, 0x73, 0x22, 0x1c, 0x0a, 0x0a, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x22, 0x3b, 0x0a, 0x0d, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e,
+	0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x52, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0x3b,
+	0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2a, 0x0a, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74,
+	0x74, 0x65, 0x6e, 0x52, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0x1f, 0x0a, 0x0d, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x22, 0x10, 0x0a, 0x0e,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x28,
+	0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x22, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x22, 0x9a, 0x01, 0x0a,
+	0x0b, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x30, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1c, 0x2e, 0x62, 0x6d, 0x69,
+	0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x45, 0x76,
+	0x65, 0x6e, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a,
+	0x0a, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12,
+	0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74,
+	0x65, 0x6e, 0x52, 0x06, 0x6b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0x2d, 0x0a, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12,
+	0x0b, 0x0a, 0x07, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07,
+	0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x02, 0x32, 0x80, 0x04, 0x0a, 0x07, 0x4b, 0x69,
+	0x74, 0x74, 0x65, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x48, 0x65, 0x6c, 0x6c, 0x6f, 0x12, 0x13,
+	0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x14, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x06, 0x53,
+	0x65, 0x61, 0x72, 0x63, 0x68, 0x12, 0x19, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1a, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x53, 0x65,
+	0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x33,
+	0x0a, 0x03, 0x47, 0x65, 0x74, 0x12, 0x16, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72,
+	0x70, 0x63, 0x2e, 0x47, 0x65, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e,
+	0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65,
+	0x6e, 0x22, 0x00, 0x12, 0x39, 0x0a, 0x06, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x12, 0x19, 0x2e,
+	0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0x00, 0x12, 0x39,
+	0x0a, 0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x19, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f,
+	0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22, 0x00, 0x12, 0x41, 0x0a, 0x06, 0x44, 0x65, 0x6c,
+	0x65, 0x74, 0x65, 0x12, 0x19, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a,
+	0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x00, 0x12, 0x45, 0x0a, 0x0b,
+	0x4c, 0x69, 0x73, 0x74, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x73, 0x12, 0x1e, 0x2e, 0x62, 0x6d,
+	0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4b, 0x69, 0x74,
+	0x74, 0x65, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x62, 0x6d,
+	0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65, 0x6e, 0x22,
+	0x00, 0x30, 0x01, 0x12, 0x47, 0x0a, 0x0c, 0x57, 0x61, 0x74, 0x63, 0x68, 0x4b, 0x69, 0x74, 0x74,
+	0x65, 0x6e, 0x73, 0x12, 0x18, 0x2e, 0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63,
+	0x2e, 0x57, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e,
+	0x62, 0x6d, 0x69, 0x67, 0x6f, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x4b, 0x69, 0x74, 0x74, 0x65,
+	0x6e, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x22, 0x00, 0x28, 0x01, 0x30, 0x01, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_kittens_proto_rawDescOnce sync.Once
+	file_kittens_proto_rawDescData = file_kittens_proto_rawDesc
+)
+
+func file_kittens_proto_rawDescGZIP() []byte {
+	file_kittens_proto_rawDescOnce.Do(func() {
+		file_kittens_proto_rawDescData = protoimpl.X.CompressGZIP(file_kittens_proto_rawDescData)
+	})
+	return file_kittens_proto_rawDescData
+}
+
+var file_kittens_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_kittens_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_kittens_proto_goTypes = []interface{}{
+	(KittenEvent_Type)(0),      // 0: bmigo.grpc.KittenEvent.Type
+	(*Request)(nil),            // 1: bmigo.grpc.Request
+	(*Response)(nil),           // 2: bmigo.grpc.Response
+	(*Kitten)(nil),             // 3: bmigo.grpc.Kitten
+	(*SearchRequest)(nil),      // 4: bmigo.grpc.SearchRequest
+	(*SearchResponse)(nil),     // 5: bmigo.grpc.SearchResponse
+	(*GetRequest)(nil),         // 6: bmigo.grpc.GetRequest
+	(*CreateRequest)(nil),      // 7: bmigo.grpc.CreateRequest
+	(*UpdateRequest)(nil),      // 8: bmigo.grpc.UpdateRequest
+	(*DeleteRequest)(nil),      // 9: bmigo.grpc.DeleteRequest
+	(*DeleteResponse)(nil),     // 10: bmigo.grpc.DeleteResponse
+	(*ListKittensRequest)(nil), // 11: bmigo.grpc.ListKittensRequest
+	(*WatchRequest)(nil),       // 12: bmigo.grpc.WatchRequest
+	(*KittenEvent)(nil),        // 13: bmigo.grpc.KittenEvent
+}
+var file_kittens_proto_depIdxs = []int32{
+	3,  // 0: bmigo.grpc.SearchResponse.kittens:type_name -> bmigo.grpc.Kitten
+	3,  // 1: bmigo.grpc.CreateRequest.kitten:type_name -> bmigo.grpc.Kitten
+	3,  // 2: bmigo.grpc.UpdateRequest.kitten:type_name -> bmigo.grpc.Kitten
+	0,  // 3: bmigo.grpc.KittenEvent.type:type_name -> bmigo.grpc.KittenEvent.Type
+	3,  // 4: bmigo.grpc.KittenEvent.kitten:type_name -> bmigo.grpc.Kitten
+	1,  // 5: bmigo.grpc.Kittens.Hello:input_type -> bmigo.grpc.Request
+	4,  // 6: bmigo.grpc.Kittens.Search:input_type -> bmigo.grpc.SearchRequest
+	6,  // 7: bmigo.grpc.Kittens.Get:input_type -> bmigo.grpc.GetRequest
+	7,  // 8: bmigo.grpc.Kittens.Create:input_type -> bmigo.grpc.CreateRequest
+	8,  // 9: bmigo.grpc.Kittens.Update:input_type -> bmigo.grpc.UpdateRequest
+	9,  // 10: bmigo.grpc.Kittens.Delete:input_type -> bmigo.grpc.DeleteRequest
+	11, // 11: bmigo.grpc.Kittens.ListKittens:input_type -> bmigo.grpc.ListKittensRequest
+	12, // 12: bmigo.grpc.Kittens.WatchKittens:input_type -> bmigo.grpc.WatchRequest
+	2,  // 13: bmigo.grpc.Kittens.Hello:output_type -> bmigo.grpc.Response
+	5,  // 14: bmigo.grpc.Kittens.Search:output_type -> bmigo.grpc.SearchResponse
+	3,  // 15: bmigo.grpc.Kittens.Get:output_type -> bmigo.grpc.Kitten
+	3,  // 16: bmigo.grpc.Kittens.Create:output_type -> bmigo.grpc.Kitten
+	3,  // 17: bmigo.grpc.Kittens.Update:output_type -> bmigo.grpc.Kitten
+	10, // 18: bmigo.grpc.Kittens.Delete:output_type -> bmigo.grpc.DeleteResponse
+	3,  // 19: bmigo.grpc.Kittens.ListKittens:output_type -> bmigo.grpc.Kitten
+	13, // 20: bmigo.grpc.Kittens.WatchKittens:output_type -> bmigo.grpc.KittenEvent
+	13, // [13:21] is the sub-list for method output_type
+	5,  // [5:13] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_kittens_proto_init() }
+func file_kittens_proto_init() {
+	if File_kittens_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_kittens_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Request); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Response); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Kitten); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SearchResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GetRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CreateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ListKittensRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*WatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_kittens_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*KittenEvent); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_kittens_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   13,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_kittens_proto_goTypes,
+		DependencyIndexes: file_kittens_proto_depIdxs,
+		EnumInfos:         file_kittens_proto_enumTypes,
+		MessageInfos:      file_kittens_proto_msgTypes,
+	}.Build()
+	File_kittens_proto = out.File
+	file_kittens_proto_rawDesc = nil
+	file_kittens_proto_goTypes = nil
+	file_kittens_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// KittensClient is the client API for Kittens service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type KittensClient interface {
+	Hello(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Kitten, error)
+	Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Kitten, error)
+	Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Kitten, error)
+	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	ListKittens(ctx context.Context, in *ListKittensRequest, opts ...grpc.CallOption) (Kittens_ListKittensClient, error)
+	WatchKittens(ctx context.Context, opts ...grpc.CallOption) (Kittens_WatchKittensClient, error)
+}
+
+type kittensClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewKittensClient(cc grpc.ClientConnInterface) KittensClient {
+	return &kittensClient{cc}
+}
+
+func (c *kittensClient) Hello(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Hello", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Search", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*Kitten, error) {
+	out := new(Kitten)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Get", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) Create(ctx context.Context, in *CreateRequest, opts ...grpc.CallOption) (*Kitten, error) {
+	out := new(Kitten)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) Update(ctx context.Context, in *UpdateRequest, opts ...grpc.CallOption) (*Kitten, error) {
+	out := new(Kitten)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Update", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error) {
+	out := new(DeleteResponse)
+	err := c.cc.Invoke(ctx, "/bmigo.grpc.Kittens/Delete", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *kittensClient) ListKittens(ctx context.Context, in *ListKittensRequest, opts ...grpc.CallOption) (Kittens_ListKittensClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Kittens_serviceDesc.Streams[0], "/bmigo.grpc.Kittens/ListKittens", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kittensListKittensClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Kittens_ListKittensClient interface {
+	Recv() (*Kitten, error)
+	grpc.ClientStream
+}
+
+type kittensListKittensClient struct {
+	grpc.ClientStream
+}
+
+func (x *kittensListKittensClient) Recv() (*Kitten, error) {
+	m := new(Kitten)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *kittensClient) WatchKittens(ctx context.Context, opts ...grpc.CallOption) (Kittens_WatchKittensClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Kittens_serviceDesc.Streams[1], "/bmigo.grpc.Kittens/WatchKittens", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &kittensWatchKittensClient{stream}
+	return x, nil
+}
+
+type Kittens_WatchKittensClient interface {
+	Send(*WatchRequest) error
+	Recv() (*KittenEvent, error)
+	grpc.ClientStream
+}
+
+type kittensWatchKittensClient struct {
+	grpc.ClientStream
+}
+
+func (x *kittensWatchKittensClient) Send(m *WatchRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *kittensWatchKittensClient) Recv() (*KittenEvent, error) {
+	m := new(KittenEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// KittensServer is the server API for Kittens service.
+type KittensServer interface {
+	Hello(context.Context, *Request) (*Response, error)
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	Get(context.Context, *GetRequest) (*Kitten, error)
+	Create(context.Context, *CreateRequest) (*Kitten, error)
+	Update(context.Context, *UpdateRequest) (*Kitten, error)
+	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	ListKittens(*ListKittensRequest, Kittens_ListKittensServer) error
+	WatchKittens(Kittens_WatchKittensServer) error
+}
+
+// UnimplementedKittensServer can be embedded to have forward compatible implementations.
+type UnimplementedKittensServer struct {
+}
+
+func (*UnimplementedKittensServer) Hello(context.Context, *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Hello not implemented")
+}
+func (*UnimplementedKittensServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (*UnimplementedKittensServer) Get(context.Context, *GetRequest) (*Kitten, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (*UnimplementedKittensServer) Create(context.Context, *CreateRequest) (*Kitten, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedKittensServer) Update(context.Context, *UpdateRequest) (*Kitten, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Update not implemented")
+}
+func (*UnimplementedKittensServer) Delete(context.Context, *DeleteRequest) (*DeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (*UnimplementedKittensServer) ListKittens(*ListKittensRequest, Kittens_ListKittensServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListKittens not implemented")
+}
+func (*UnimplementedKittensServer) WatchKittens(Kittens_WatchKittensServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchKittens not implemented")
+}
+
+func RegisterKittensServer(s *grpc.Server, srv KittensServer) {
+	s.RegisterService(&_Kittens_serviceDesc, srv)
+}
+
+func _Kittens_Hello_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Hello(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Hello",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Hello(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Search",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Get",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Get(ctx, req.(*GetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Create(ctx, req.(*CreateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_Update_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Update(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Update",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Update(ctx, req.(*UpdateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KittensServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/bmigo.grpc.Kittens/Delete",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KittensServer).Delete(ctx, req.(*DeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Kittens_ListKittens_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListKittensRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KittensServer).ListKittens(m, &kittensListKittensServer{stream})
+}
+
+type Kittens_ListKittensServer interface {
+	Send(*Kitten) error
+	grpc.ServerStream
+}
+
+type kittensListKittensServer struct {
+	grpc.ServerStream
+}
+
+func (x *kittensListKittensServer) Send(m *Kitten) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Kittens_WatchKittens_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(KittensServer).WatchKittens(&kittensWatchKittensServer{stream})
+}
+
+type Kittens_WatchKittensServer interface {
+	Send(*KittenEvent) error
+	Recv() (*WatchRequest, error)
+	grpc.ServerStream
+}
+
+type kittensWatchKittensServer struct {
+	grpc.ServerStream
+}
+
+func (x *kittensWatchKittensServer) Send(m *KittenEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *kittensWatchKittensServer) Recv() (*WatchRequest, error) {
+	m := new(WatchRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Kittens_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "bmigo.grpc.Kittens",
 	HandlerType: (*KittensServer)(nil),
 	Methods: []grpc.MethodDesc{
@@ -292,7 +1384,39 @@ var _Kittens_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Hello",
 			Handler:    _Kittens_Hello_Handler,
 		},
+		{
+			MethodName: "Search",
+			Handler:    _Kittens_Search_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _Kittens_Get_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _Kittens_Create_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Kittens_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Kittens_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListKittens",
+			Handler:       _Kittens_ListKittens_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchKittens",
+			Handler:       _Kittens_WatchKittens_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "kittens.proto",
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+)
+
+// kittensService 헬스 체크에서 사용하는 서비스 이름
+const kittensService = "bmigo.grpc.Kittens"
+
+// updateHealth 저장소 상태를 health 서비스에 반영, 서버 전체("")와
+// Kittens 서비스 상태를 함께 변경
+func updateHealth(hs *health.Server, store data.Store) {
+	status := healthpb.HealthCheckResponse_SERVING
+	if pinger, ok := store.(data.Pinger); ok && pinger.Ping() != nil {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+	}
+
+	hs.SetServingStatus("", status)
+	hs.SetServingStatus(kittensService, status)
+}
+
+// watchHealth 주기적으로 저장소 상태를 확인, stop 이 닫히면 종료
+func watchHealth(hs *health.Server, store data.Store, interval time.Duration, stop <-chan struct{}) {
+	updateHealth(hs, store)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			updateHealth(hs, store)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// gracefulStop 진행 중인 RPC 가 끝나길 기다리고, timeout 이 지나면
+// 남은 연결을 강제로 종료
+func gracefulStop(s *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		s.Stop()
+		<-done
+	}
+}
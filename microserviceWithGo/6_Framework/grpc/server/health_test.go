@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	context "golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittens"
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+)
+
+// pingStore is a store whose database can be made unreachable
+type pingStore struct {
+	data.MemoryStore
+	err error
+}
+
+func (p *pingStore) Ping() error {
+	return p.err
+}
+
+func startHealthServer(t *testing.T, store data.Store) (*grpc.Server, *health.Server, *grpc.ClientConn) {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer()
+	proto.RegisterKittensServer(s, kittens.New(store))
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(s, hs)
+	reflection.Register(s)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithInsecure(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return s, hs, conn
+}
+
+func check(t *testing.T, conn *grpc.ClientConn, service string) healthpb.HealthCheckResponse_ServingStatus {
+	response, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	assert.Nil(t, err)
+
+	return response.GetStatus()
+}
+
+func TestHealthFollowsStore(t *testing.T) {
+	store := &pingStore{}
+	_, hs, conn := startHealthServer(t, store)
+
+	updateHealth(hs, store)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, conn, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(t, conn, kittensService))
+
+	store.err = errors.New("no reachable servers")
+	updateHealth(hs, store)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(t, conn, kittensService))
+}
+
+func TestStoreWithoutPingIsAlwaysServing(t *testing.T) {
+	_, hs, conn := startHealthServer(t, &data.MemoryStore{})
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go watchHealth(hs, &data.MemoryStore{}, time.Hour, stop)
+
+	assert.Eventually(t, func() bool {
+		return check(t, conn, kittensService) == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+}
+
+func TestReflectionListsServices(t *testing.T) {
+	_, _, conn := startHealthServer(t, &data.MemoryStore{})
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+	assert.Nil(t, err)
+	stream.Send(&rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_ListServices{}})
+	response, err := stream.Recv()
+	assert.Nil(t, err)
+
+	services := []string{}
+	for _, s := range response.GetListServicesResponse().Service {
+		services = append(services, s.Name)
+	}
+	assert.Contains(t, services, kittensService)
+	assert.Contains(t, services, "grpc.health.v1.Health")
+}
+
+func TestGracefulStopWaitsForCallsThenForcesStop(t *testing.T) {
+	s, _, conn := startHealthServer(t, &data.MemoryStore{})
+	client := proto.NewKittensClient(conn)
+
+	// a watch stream never finishes on its own
+	stream, err := client.WatchKittens(context.Background())
+	assert.Nil(t, err)
+	stream.Send(&proto.WatchRequest{})
+
+	start := time.Now()
+	gracefulStop(s, 50*time.Millisecond)
+
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+}
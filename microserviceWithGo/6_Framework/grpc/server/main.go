@@ -4,29 +4,113 @@ import (
 	"fmt"
 	"log"
 	"net"
-
-	context "golang.org/x/net/context"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/data"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/4_Test/handlers"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/interceptor"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/kittens"
 	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/tlsconfig"
 )
 
-type kittenServer struct{} // 핸들러 정의
-
-func (k *kittenServer) Hello(ctx context.Context, request *proto.Request) (*proto.Response, error) {
-	response := &proto.Response{} // 응답 객체 생성
-	response.Msg = fmt.Sprintf("Hello %v", request.Name)
+func main() {
+	store := &data.MemoryStore{}
 
-	return response, nil
-}
+	// HTTP 검색 핸들러도 같은 저장소 사용
+	go func() {
+		log.Fatal(http.ListenAndServe(":8323", &handlers.Search{DataStore: store}))
+	}()
 
-func main() {
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", 9000)) // 리스너 생성
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
-	proto.RegisterKittensServer(grpcServer, &kittenServer{}) // 서버 인스턴스 생성
-	grpcServer.Serve(lis)
+
+	token := os.Getenv("KITTENS_TOKEN")
+	if token == "" {
+		token = "kittens"
+	}
+
+	// 로깅 -> 메트릭 -> 패닉 복구 -> 인증 순서로 실행
+	logger := log.New(os.Stdout, "", log.LstdFlags)
+	metrics := interceptor.NewMetrics()
+	auth := &interceptor.TokenAuth{
+		Valid: interceptor.StaticToken(token),
+		// 오케스트레이터와 grpcurl 은 토큰 없이 접근
+		Skip: []string{
+			"/grpc.health.v1.Health/Check",
+			"/grpc.health.v1.Health/Watch",
+			"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+		},
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(
+			interceptor.UnaryServerLogger(logger),
+			metrics.UnaryServerInterceptor(),
+			interceptor.UnaryServerRecovery(logger),
+			auth.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptor.StreamServerLogger(logger),
+			metrics.StreamServerInterceptor(),
+			interceptor.StreamServerRecovery(logger),
+			auth.StreamServerInterceptor(),
+		),
+	}
+
+	// 인증서가 주어지면 TLS 사용, CA 가 있으면 클라이언트 인증서도 요구 (mTLS)
+	// 파일이 교체되면 1분 안에 새 인증서로 변경
+	if cert := os.Getenv("KITTENS_TLS_CERT"); cert != "" {
+		reloader, err := tlsconfig.NewReloader(tlsconfig.Files{
+			Cert: cert,
+			Key:  os.Getenv("KITTENS_TLS_KEY"),
+			CA:   os.Getenv("KITTENS_TLS_CA"),
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		reloader.Start(time.Minute, func(err error) { logger.Println(err) })
+		defer reloader.Stop()
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(reloader.ServerConfig())))
+	}
+
+	grpcServer := grpc.NewServer(opts...)
+	proto.RegisterKittensServer(grpcServer, kittens.New(store)) // 서버 인스턴스 생성
+
+	// 표준 헬스 체크 서비스와 리플렉션 등록
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	stop := make(chan struct{})
+	go watchHealth(healthServer, store, 10*time.Second, stop)
+
+	// SIGTERM 을 받으면 NOT_SERVING 으로 바꾼 뒤 진행 중인 요청을 마치고 종료
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+		<-signals
+
+		logger.Println("shutting down")
+		close(stop)
+		healthServer.Shutdown()
+		gracefulStop(grpcServer, 30*time.Second)
+	}()
+
+	if err := grpcServer.Serve(lis); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
 }
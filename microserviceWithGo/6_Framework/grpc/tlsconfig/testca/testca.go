@@ -0,0 +1,146 @@
+// Package testca creates throwaway certificate authorities for tests, so
+// that the TLS code paths can be exercised without any external PKI.
+package testca
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"time"
+)
+
+// CA is a self signed certificate authority held in memory
+type CA struct {
+	Cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	// CertPEM is the PEM encoded certificate of the CA
+	CertPEM []byte
+}
+
+// Pair is a PEM encoded certificate and private key
+type Pair struct {
+	CertPEM []byte
+	KeyPEM  []byte
+}
+
+// New creates a CA which is valid for a day
+func New(name string) (*CA, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          serial(),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CA{
+		Cert:    cert,
+		key:     key,
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}, nil
+}
+
+// Issue creates a certificate signed by the CA which can be used by both
+// servers and clients. Hosts are added as DNS names or IP addresses.
+func (c *CA) Issue(commonName string, hosts ...string) (Pair, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial(),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, h)
+		}
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, c.Cert, &key.PublicKey, c.key)
+	if err != nil {
+		return Pair{}, err
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return Pair{}, err
+	}
+
+	return Pair{
+		CertPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		KeyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}, nil
+}
+
+// Pool returns a pool which trusts only the CA
+func (c *CA) Pool() *x509.CertPool {
+	pool := x509.NewCertPool()
+	pool.AddCert(c.Cert)
+
+	return pool
+}
+
+// WriteCA writes the certificate of the CA to dir/name.crt and returns the
+// path
+func (c *CA) WriteCA(dir, name string) (string, error) {
+	path := filepath.Join(dir, name+".crt")
+
+	return path, ioutil.WriteFile(path, c.CertPEM, 0644)
+}
+
+// Write writes the pair to dir/name.crt and dir/name.key and returns the
+// paths
+func (p Pair) Write(dir, name string) (cert, key string, err error) {
+	cert = filepath.Join(dir, name+".crt")
+	key = filepath.Join(dir, name+".key")
+
+	if err := ioutil.WriteFile(cert, p.CertPEM, 0644); err != nil {
+		return "", "", err
+	}
+	if err := ioutil.WriteFile(key, p.KeyPEM, 0600); err != nil {
+		return "", "", err
+	}
+
+	return cert, key, nil
+}
+
+// TLSCertificate parses the pair
+func (p Pair) TLSCertificate() (tls.Certificate, error) {
+	return tls.X509KeyPair(p.CertPEM, p.KeyPEM)
+}
+
+func serial() *big.Int {
+	n, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	return n
+}
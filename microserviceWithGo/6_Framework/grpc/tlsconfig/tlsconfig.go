@@ -0,0 +1,233 @@
+// Package tlsconfig loads certificates for TLS and mutual TLS from files and
+// reloads them when the files are rotated, without restarting the server or
+// dropping existing connections.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Files are the paths of the PEM encoded certificates
+type Files struct {
+	// Cert and Key are the certificate and private key presented to the
+	// other side, they are optional for clients which do not use mutual
+	// TLS.
+	Cert string
+	Key  string
+	// CA is the bundle the certificate of the other side is verified
+	// with. A server with a CA requires clients to present a certificate,
+	// a client without one uses the system roots.
+	CA string
+}
+
+// Reloader holds the certificates loaded from Files and reloads them when the
+// files change. New connections use the latest certificates.
+type Reloader struct {
+	files Files
+
+	mutex    sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewReloader loads the files, an error is returned when they can not be
+// loaded
+func NewReloader(files Files) (*Reloader, error) {
+	if (files.Cert == "") != (files.Key == "") {
+		return nil, errors.New("tlsconfig: cert and key must be given together")
+	}
+
+	r := &Reloader{files: files}
+	if _, err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload loads the files again when any of them has changed and reports
+// whether it did. The current certificates are kept when loading fails, e.g.
+// when the certificate has been replaced but the key not yet.
+func (r *Reloader) Reload() (bool, error) {
+	modTimes := map[string]time.Time{}
+	for _, path := range []string{r.files.Cert, r.files.Key, r.files.CA} {
+		if path == "" {
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return false, fmt.Errorf("tlsconfig: %w", err)
+		}
+		modTimes[path] = info.ModTime()
+	}
+
+	r.mutex.RLock()
+	changed := !equal(modTimes, r.modTimes)
+	r.mutex.RUnlock()
+
+	if !changed {
+		return false, nil
+	}
+
+	var cert *tls.Certificate
+	if r.files.Cert != "" {
+		c, err := tls.LoadX509KeyPair(r.files.Cert, r.files.Key)
+		if err != nil {
+			return false, fmt.Errorf("tlsconfig: loading key pair: %w", err)
+		}
+		cert = &c
+	}
+
+	var pool *x509.CertPool
+	if r.files.CA != "" {
+		pem, err := ioutil.ReadFile(r.files.CA)
+		if err != nil {
+			return false, fmt.Errorf("tlsconfig: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return false, fmt.Errorf("tlsconfig: no certificates found in %v", r.files.CA)
+		}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.cert = cert
+	r.pool = pool
+	r.modTimes = modTimes
+
+	return true, nil
+}
+
+// Start checks the files for changes at the given interval until Stop is
+// called, failed reloads are passed to onError which may be nil
+func (r *Reloader) Start(interval time.Duration, onError func(err error)) {
+	r.mutex.Lock()
+	if r.stop != nil {
+		r.mutex.Unlock()
+		return
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	stop, done := r.stop, r.done
+	r.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := r.Reload(); err != nil && onError != nil {
+					onError(err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops checking the files for changes
+func (r *Reloader) Stop() {
+	r.mutex.Lock()
+	stop, done := r.stop, r.done
+	r.stop, r.done = nil, nil
+	r.mutex.Unlock()
+
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+}
+
+// Certificate returns the current certificate, nil when no key pair was
+// given
+func (r *Reloader) Certificate() *tls.Certificate {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.cert
+}
+
+// ServerConfig returns the config for a server, every handshake uses the
+// current certificate and client CA. Clients must present a certificate
+// signed by the CA when one was given.
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mutex.RLock()
+			defer r.mutex.RUnlock()
+
+			if r.cert == nil {
+				return nil, errors.New("tlsconfig: server has no certificate")
+			}
+
+			c := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2"},
+			}
+			if r.pool != nil {
+				c.ClientCAs = r.pool
+				c.ClientAuth = tls.RequireAndVerifyClientCert
+			}
+
+			return c, nil
+		},
+	}
+}
+
+// ClientConfig returns the config for a client connecting to serverName.
+// Every handshake presents the current certificate, the CA is read when the
+// config is created so a new config is needed after it changes.
+func (r *Reloader) ClientConfig(serverName string) *tls.Config {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: serverName,
+		RootCAs:    r.pool,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			if cert := r.Certificate(); cert != nil {
+				return cert, nil
+			}
+
+			// no certificate, the server decides whether that is allowed
+			return &tls.Certificate{}, nil
+		},
+	}
+}
+
+func equal(a, b map[string]time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if !b[k].Equal(v) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,209 @@
+package tlsconfig
+
+import (
+	"context"
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+
+	proto "github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/proto"
+	"github.com/Sungchul-P/go-learning/microserviceWithGo/6_Framework/grpc/tlsconfig/testca"
+)
+
+type helloServer struct {
+	proto.UnimplementedKittensServer
+}
+
+// Hello returns the common name of the client certificate
+func (h *helloServer) Hello(ctx context.Context, request *proto.Request) (*proto.Response, error) {
+	p, _ := peer.FromContext(ctx)
+	info := p.AuthInfo.(credentials.TLSInfo)
+
+	name := "anonymous"
+	if len(info.State.PeerCertificates) > 0 {
+		name = info.State.PeerCertificates[0].Subject.CommonName
+	}
+
+	return &proto.Response{Msg: name}, nil
+}
+
+// writeFiles issues a certificate and writes it together with the CA to dir
+func writeFiles(t *testing.T, ca *testca.CA, dir, name string) Files {
+	pair, err := ca.Issue(name, "kittens.local")
+	assert.Nil(t, err)
+
+	cert, key, err := pair.Write(dir, name)
+	assert.Nil(t, err)
+	caPath, err := ca.WriteCA(dir, name+"-ca")
+	assert.Nil(t, err)
+
+	return Files{Cert: cert, Key: key, CA: caPath}
+}
+
+func startServer(t *testing.T, config *Reloader) *bufconn.Listener {
+	lis := bufconn.Listen(1024 * 1024)
+	s := grpc.NewServer(grpc.Creds(credentials.NewTLS(config.ServerConfig())))
+	proto.RegisterKittensServer(s, &helloServer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	return lis
+}
+
+// hello connects to the server and returns the response or the error
+func hello(t *testing.T, lis *bufconn.Listener, config *Reloader) (string, *proto.Response, error) {
+	var serverName string
+	conn, err := grpc.Dial("kittens.local",
+		grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(credentials.NewTLS(config.ClientConfig("kittens.local"))),
+	)
+	if err != nil {
+		return "", nil, err
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	p := &peer.Peer{}
+	response, err := proto.NewKittensClient(conn).Hello(ctx, &proto.Request{}, grpc.Peer(p))
+	if err == nil {
+		serverName = p.AuthInfo.(credentials.TLSInfo).State.PeerCertificates[0].Subject.CommonName
+	}
+
+	return serverName, response, err
+}
+
+func TestMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	server, err := NewReloader(writeFiles(t, ca, dir, "server"))
+	assert.Nil(t, err)
+	client, err := NewReloader(writeFiles(t, ca, dir, "client"))
+	assert.Nil(t, err)
+
+	_, response, err := hello(t, startServer(t, server), client)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "client", response.Msg)
+}
+
+func TestServerWithoutCAAcceptsClientsWithoutCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	files := writeFiles(t, ca, dir, "server")
+	server, _ := NewReloader(Files{Cert: files.Cert, Key: files.Key})
+	client, _ := NewReloader(Files{CA: files.CA})
+
+	_, response, err := hello(t, startServer(t, server), client)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "anonymous", response.Msg)
+}
+
+func TestRejectsClientWithoutCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	files := writeFiles(t, ca, dir, "server")
+	server, _ := NewReloader(files)
+	client, _ := NewReloader(Files{CA: files.CA})
+
+	_, _, err := hello(t, startServer(t, server), client)
+
+	assert.NotNil(t, err)
+}
+
+func TestRejectsCertificateFromOtherCA(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	other, _ := testca.New("other CA")
+	server, _ := NewReloader(writeFiles(t, ca, dir, "server"))
+	client, _ := NewReloader(writeFiles(t, other, dir, "client"))
+
+	_, _, err := hello(t, startServer(t, server), client)
+
+	assert.NotNil(t, err)
+}
+
+func TestReloadsRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	files := writeFiles(t, ca, dir, "server")
+	server, _ := NewReloader(files)
+	client, _ := NewReloader(writeFiles(t, ca, dir, "client"))
+	lis := startServer(t, server)
+
+	name, _, err := hello(t, lis, client)
+	assert.Nil(t, err)
+	assert.Equal(t, "server", name)
+
+	// rotate the server certificate in place
+	pair, _ := ca.Issue("rotated", "kittens.local")
+	ioutil.WriteFile(files.Cert, pair.CertPEM, 0644)
+	ioutil.WriteFile(files.Key, pair.KeyPEM, 0600)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(files.Cert, later, later)
+
+	reloaded, err := server.Reload()
+	assert.Nil(t, err)
+	assert.True(t, reloaded)
+
+	name, _, err = hello(t, lis, client)
+	assert.Nil(t, err)
+	assert.Equal(t, "rotated", name)
+}
+
+func TestKeepsCertificateWhenReloadFails(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	files := writeFiles(t, ca, dir, "server")
+	server, _ := NewReloader(files)
+	before := server.Certificate()
+
+	// only the certificate has been replaced so far
+	pair, _ := ca.Issue("rotated")
+	ioutil.WriteFile(files.Cert, pair.CertPEM, 0644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(files.Cert, later, later)
+
+	_, err := server.Reload()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, before, server.Certificate())
+}
+
+func TestStartReloadsPeriodically(t *testing.T) {
+	dir := t.TempDir()
+	ca, _ := testca.New("kittens CA")
+	files := writeFiles(t, ca, dir, "server")
+	server, _ := NewReloader(files)
+	before := server.Certificate()
+
+	server.Start(time.Millisecond, nil)
+	defer server.Stop()
+
+	pair, _ := ca.Issue("rotated")
+	ioutil.WriteFile(files.Key, pair.KeyPEM, 0600)
+	ioutil.WriteFile(files.Cert, pair.CertPEM, 0644)
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(files.Cert, later, later)
+
+	for i := 0; i < 1000 && server.Certificate() == before; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	assert.NotEqual(t, before, server.Certificate())
+}
+
+func TestNewReloaderRequiresCertAndKeyTogether(t *testing.T) {
+	_, err := NewReloader(Files{Cert: "server.crt"})
+
+	assert.NotNil(t, err)
+}